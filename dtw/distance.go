@@ -0,0 +1,105 @@
+package dtw
+
+import (
+	"math"
+)
+
+// LocalDistance computes the distance between an observation of the input
+// sequence and that of the template.
+type LocalDistance interface {
+	Distance(x, y []float64) float64
+}
+
+// DistanceFunc is an adapter to use an ordinary function as LocalDistance.
+type DistanceFunc func(x, y []float64) float64
+
+// Distance returns f(x, y).
+func (f DistanceFunc) Distance(x, y []float64) float64 {
+	return f(x, y)
+}
+
+// SquaredEuclideanDistance is the squared euclidean distance, which is the
+// default local distance of DTW.
+type SquaredEuclideanDistance struct{}
+
+// Distance returns the squared euclidean distance between x and y.
+func (SquaredEuclideanDistance) Distance(x, y []float64) float64 {
+	sum := 0.0
+	for i := range x {
+		sum += (x[i] - y[i]) * (x[i] - y[i])
+	}
+	return sum
+}
+
+// EuclideanDistance is the euclidean distance.
+type EuclideanDistance struct{}
+
+// Distance returns the euclidean distance between x and y.
+func (EuclideanDistance) Distance(x, y []float64) float64 {
+	return math.Sqrt(SquaredEuclideanDistance{}.Distance(x, y))
+}
+
+// CosineDistance is one minus the cosine similarity.
+type CosineDistance struct{}
+
+// Distance returns the cosine distance between x and y. It returns 1 if
+// either of them is a zero vector.
+func (CosineDistance) Distance(x, y []float64) float64 {
+	dot, normX, normY := 0.0, 0.0, 0.0
+	for i := range x {
+		dot += x[i] * y[i]
+		normX += x[i] * x[i]
+		normY += y[i] * y[i]
+	}
+	if normX == 0.0 || normY == 0.0 {
+		return 1.0
+	}
+	return 1.0 - dot/math.Sqrt(normX*normY)
+}
+
+// MahalanobisDistance is the mahalanobis distance given the inverse of a
+// covariance matrix.
+type MahalanobisDistance struct {
+	InverseCovariance [][]float64
+}
+
+// NewDiagonalMahalanobisDistance returns a mahalanobis distance for a
+// diagonal covariance matrix given its variances.
+func NewDiagonalMahalanobisDistance(variance []float64) *MahalanobisDistance {
+	inverse := make([][]float64, len(variance))
+	for i := range variance {
+		inverse[i] = make([]float64, len(variance))
+		inverse[i][i] = 1.0 / variance[i]
+	}
+	return &MahalanobisDistance{InverseCovariance: inverse}
+}
+
+// Distance returns the mahalanobis distance between x and y.
+func (m *MahalanobisDistance) Distance(x, y []float64) float64 {
+	sum := 0.0
+	for i := range x {
+		for j := range y {
+			sum += (x[i] - y[i]) * m.InverseCovariance[i][j] * (x[j] - y[j])
+		}
+	}
+	return math.Sqrt(sum)
+}
+
+// MelCepstralDistance is the mel-cepstral distortion in dB,
+// (10/ln10) sqrt(2 sum (c(d) - c'(d))^2).
+type MelCepstralDistance struct {
+	ExcludeC0 bool // Exclude power coefficient c(0)
+}
+
+// Distance returns the mel-cepstral distortion between x and y.
+func (m MelCepstralDistance) Distance(x, y []float64) float64 {
+	start := 0
+	if m.ExcludeC0 {
+		start = 1
+	}
+	sum := 0.0
+	for i := start; i < len(x); i++ {
+		sum += (x[i] - y[i]) * (x[i] - y[i])
+	}
+	return 10.0 / math.Ln10 * math.Sqrt(2.0*sum)
+}
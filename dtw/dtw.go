@@ -3,13 +3,19 @@
 package dtw
 
 // DTW represents dynamic time warping that can be used to align two
-// time-series.
+// time-series. If Pattern is nil, DTW performs frame-synchronous
+// recursion, where the template index moves from j to i for each frame
+// of the input sequence with i-BackwardStep <= j <= i+ForwardStep.
+// Otherwise, the path is constrained by the step pattern.
 type DTW struct {
 	ForwardStep      int
 	BackwardStep     int
 	Template         [][]float64
 	CostTable        [][]float64
 	BackTracePointer [][]int
+	Distance         LocalDistance  // SquaredEuclideanDistance if nil
+	Transition       TransitionCost // DefaultTransitionCost if nil and Pattern is nil
+	Pattern          *StepPattern
 }
 
 // SetTemplate sets the template to align an input sequence.
@@ -28,8 +34,17 @@ func (d *DTW) SetTemplate(template [][]float64) {
 	}
 }
 
-// DTW peforms dynamic programming to align an input sequence to template.
+// DTW peforms dynamic programming to align an input sequence to template
+// and returns the template index for each frame of the sequence.
 func (d *DTW) DTW(sequence [][]float64) []int {
+	if d.Pattern != nil {
+		path, _ := d.Align(sequence)
+		if path == nil {
+			return nil
+		}
+		return templateIndices(path, len(sequence))
+	}
+
 	// Forward recursion
 	for i := 0; i < len(sequence); i++ {
 		d.Update(sequence[i])
@@ -38,26 +53,22 @@ func (d *DTW) DTW(sequence [][]float64) []int {
 	return d.BackTrace()
 }
 
-// TODO(ryuichi) use interface
+// ObservationCost returns the local distance between an observation and
+// the template at the index.
 func (d *DTW) ObservationCost(v []float64, index int) float64 {
-	t := d.Template[index]
-	cost := 0.0
-	for i := range t {
-		cost += (v[i] - t[i]) * (v[i] - t[i])
+	if d.Distance == nil {
+		return SquaredEuclideanDistance{}.Distance(v, d.Template[index])
 	}
-	return cost
+	return d.Distance.Distance(v, d.Template[index])
 }
 
-// TODO(ryuichi) use interface
+// TransitionCost returns the penalty of a transition from the template
+// index i to j.
 func (d *DTW) TransitionCost(i, j int) float64 {
-	switch {
-	case j == i+1:
-		return 0
-	case i == j:
-		return 1
-	default:
-		return 2
+	if d.Transition == nil {
+		return DefaultTransitionCost().Cost(i, j)
 	}
+	return d.Transition.Cost(i, j)
 }
 
 // Update performs one stop of forward recursion.
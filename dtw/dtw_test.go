@@ -1,6 +1,8 @@
 package dtw
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -35,3 +37,169 @@ func TestDTW(t *testing.T) {
 		}
 	}
 }
+
+func TestLocalDistance(t *testing.T) {
+	x, y := []float64{1, 2, 2}, []float64{1, 0, 0}
+
+	d := SquaredEuclideanDistance{}.Distance(x, y)
+	if d != 8 {
+		t.Errorf("Squared euclidean distance %f, want 8.", d)
+	}
+	d = EuclideanDistance{}.Distance(x, y)
+	if math.Abs(d-math.Sqrt(8)) > 1.0e-12 {
+		t.Errorf("Euclidean distance %f, want %f.", d, math.Sqrt(8))
+	}
+	d = CosineDistance{}.Distance(x, y)
+	if math.Abs(d-2.0/3.0) > 1.0e-12 {
+		t.Errorf("Cosine distance %f, want %f.", d, 2.0/3.0)
+	}
+	m := NewDiagonalMahalanobisDistance([]float64{1, 4, 16})
+	d = m.Distance(x, y)
+	if math.Abs(d-math.Sqrt(1.25)) > 1.0e-12 {
+		t.Errorf("Mahalanobis distance %f, want %f.", d, math.Sqrt(1.25))
+	}
+	expected := 10.0 / math.Ln10 * math.Sqrt(2*8)
+	d = MelCepstralDistance{ExcludeC0: true}.Distance(x, y)
+	if math.Abs(d-expected) > 1.0e-12 {
+		t.Errorf("Mel-cepstral distance %f, want %f.", d, expected)
+	}
+}
+
+func TestCustomDistanceAndTransition(t *testing.T) {
+	z1 := [][]float64{{0}, {1}, {2}, {3}, {4}, {5}}
+	z2 := [][]float64{{0}, {0}, {1}, {2}, {3}, {4}, {4}, {5}}
+
+	d := &DTW{
+		ForwardStep:  0,
+		BackwardStep: 1,
+		Distance: DistanceFunc(func(x, y []float64) float64 {
+			return math.Abs(x[0] - y[0])
+		}),
+		Transition: &PenaltyTransitionCost{Advance: 0, Stay: 0.5, Other: 1},
+	}
+	d.SetTemplate(z1)
+	path := d.DTW(z2)
+
+	expected := []int{0, 0, 1, 2, 3, 4, 4, 5}
+	for i := range expected {
+		if expected[i] != path[i] {
+			t.Error("Result", path, "Expected", expected)
+			break
+		}
+	}
+}
+
+func TestStepPatterns(t *testing.T) {
+	template := make([][]float64, 10)
+	for i := range template {
+		template[i] = []float64{float64(i)}
+	}
+	expected := []int{0, 1, 2, 2, 3, 4, 5, 6, 6, 7, 8, 9}
+	sequence := make([][]float64, len(expected))
+	for i := range sequence {
+		sequence[i] = template[expected[i]]
+	}
+
+	for _, pattern := range []*StepPattern{Symmetric1, Symmetric2, Asymmetric,
+		Itakura, RabinerJuangTypeI, RabinerJuangTypeII, RabinerJuangTypeIII,
+		RabinerJuangTypeV, RabinerJuangTypeVI} {
+		d := &DTW{Pattern: pattern}
+		d.SetTemplate(template)
+
+		path, cost := d.Align(sequence)
+		if path == nil {
+			t.Errorf("Pattern %v: no path found.", pattern)
+			continue
+		}
+		if path[0] != [2]int{0, 0} ||
+			path[len(path)-1] != [2]int{len(sequence) - 1, len(template) - 1} {
+			t.Errorf("Pattern %v: path %v, want from origin to end.",
+				pattern, path)
+		}
+		for k := 1; k < len(path); k++ {
+			if path[k][0] < path[k-1][0] || path[k][1] < path[k-1][1] {
+				t.Errorf("Pattern %v: path %v, want monotonic.", pattern, path)
+				break
+			}
+		}
+		if cost != 0 {
+			t.Errorf("Pattern %v: cost %f, want 0.", pattern, cost)
+		}
+
+		// Type III skips frames, whose indices are ambiguous
+		if pattern == RabinerJuangTypeIII {
+			continue
+		}
+		indices := d.DTW(sequence)
+		for i := range expected {
+			if expected[i] != indices[i] {
+				t.Errorf("Pattern %v: result %v, want %v.", pattern,
+					indices, expected)
+				break
+			}
+		}
+	}
+
+	// The asymmetric pattern cannot reach the end if the template is more
+	// than twice as long as the sequence
+	d := &DTW{Pattern: Asymmetric}
+	d.SetTemplate(template)
+	if path, cost := d.Align(sequence[:4]); path != nil || !math.IsInf(cost, 1) {
+		t.Errorf("Path %v with cost %f, want nil and +Inf.", path, cost)
+	}
+}
+
+func TestNormalization(t *testing.T) {
+	template := [][]float64{{0}, {0}, {0}}
+	sequence := [][]float64{{1}, {1}, {1}}
+
+	d := &DTW{Pattern: Symmetric2}
+	d.SetTemplate(template)
+	// 1 at the origin and 2 for each of two diagonal steps
+	if _, cost := d.Align(sequence); math.Abs(cost-5.0/6.0) > 1.0e-12 {
+		t.Errorf("Cost %f, want %f.", cost, 5.0/6.0)
+	}
+
+	d.Pattern = Asymmetric
+	if _, cost := d.Align(sequence); math.Abs(cost-1.0) > 1.0e-12 {
+		t.Errorf("Cost %f, want 1.", cost)
+	}
+}
+
+func TestItakuraSlope(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	randomSequence := func(length int) [][]float64 {
+		sequence := make([][]float64, length)
+		for i := range sequence {
+			sequence[i] = []float64{r.NormFloat64()}
+		}
+		return sequence
+	}
+
+	n := 9
+	for m := 1; m <= 2*n+1; m++ {
+		d := &DTW{Pattern: Itakura}
+		d.SetTemplate(randomSequence(m))
+		path, _ := d.Align(randomSequence(n))
+
+		// Reachable if and only if the slope is between 1/2 and 2
+		reachable := 2*(m-1) >= n-1 && m-1 <= 2*(n-1)
+		if (path != nil) != reachable {
+			t.Errorf("Template length %d: path %v, want reachable %v.",
+				m, path, reachable)
+			continue
+		}
+
+		for k := 1; k < len(path); k++ {
+			dt, di := path[k][0]-path[k-1][0], path[k][1]-path[k-1][1]
+			if dt != 1 || di < 0 || di > 2 {
+				t.Errorf("Template length %d: step (%d, %d), want (1, 0-2).",
+					m, dt, di)
+			}
+			if k > 1 && di == 0 && path[k-1][1] == path[k-2][1] {
+				t.Errorf("Template length %d: path %v stays twice in a row.",
+					m, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package dtw
+
+import (
+	"math"
+)
+
+// Normalization specifies how the cumulative cost is normalized.
+type Normalization int
+
+const (
+	NoNormalization     Normalization = iota // No normalization
+	NormalizeBySequence                      // Divide by the length of the sequence
+	NormalizeByTemplate                      // Divide by the length of the template
+	NormalizeBySum                           // Divide by the sum of both lengths
+)
+
+// Step represents a point of a local path relative to the end point,
+// where T is the offset of the input sequence and I is that of the
+// template. Weight is multiplied to the local distance at the point.
+type Step struct {
+	T, I   int
+	Weight float64
+}
+
+// LocalPath represents a sequence of points from the start point to the
+// end point (0, 0). The weight of the start point is ignored because the
+// cumulative cost at the point includes its local distance.
+type LocalPath []Step
+
+// StepPattern represents local continuity constraints and slope weighting
+// of DTW.
+type StepPattern struct {
+	Paths         []LocalPath
+	Normalization Normalization
+}
+
+// Symmetric1 allows horizontal, vertical and diagonal steps with equal
+// weights.
+var Symmetric1 = &StepPattern{
+	Paths: []LocalPath{
+		{{-1, 0, 0}, {0, 0, 1}},
+		{{-1, -1, 0}, {0, 0, 1}},
+		{{0, -1, 0}, {0, 0, 1}},
+	},
+	Normalization: NoNormalization,
+}
+
+// Symmetric2 allows horizontal, vertical and diagonal steps, where the
+// diagonal step is weighted twice.
+var Symmetric2 = &StepPattern{
+	Paths: []LocalPath{
+		{{-1, 0, 0}, {0, 0, 1}},
+		{{-1, -1, 0}, {0, 0, 2}},
+		{{0, -1, 0}, {0, 0, 1}},
+	},
+	Normalization: NormalizeBySum,
+}
+
+// Asymmetric advances the sequence by exactly one frame and the template
+// by zero, one or two frames.
+var Asymmetric = &StepPattern{
+	Paths: []LocalPath{
+		{{-1, 0, 0}, {0, 0, 1}},
+		{{-1, -1, 0}, {0, 0, 1}},
+		{{-1, -2, 0}, {0, 0, 1}},
+	},
+	Normalization: NormalizeBySequence,
+}
+
+// Itakura advances the sequence by exactly one frame and the template by
+// zero, one or two frames, where staying at the same template frame is
+// always followed by advancing. The template never stays twice in a row,
+// which limits the slope between 1/2 and 2.
+var Itakura = &StepPattern{
+	Paths: []LocalPath{
+		{{-1, -1, 0}, {0, 0, 1}},
+		{{-1, -2, 0}, {0, 0, 1}},
+		{{-2, -1, 0}, {-1, -1, 1}, {0, 0, 1}},
+		{{-2, -2, 0}, {-1, -2, 1}, {0, 0, 1}},
+	},
+	Normalization: NormalizeBySequence,
+}
+
+// Rabiner-Juang local continuity constraints with the symmetric slope
+// weighting, where each step is weighted by the sum of its horizontal and
+// vertical lengths.
+var (
+	RabinerJuangTypeI = &StepPattern{
+		Paths: []LocalPath{
+			{{-1, 0, 0}, {0, 0, 1}},
+			{{-1, -1, 0}, {0, 0, 2}},
+			{{0, -1, 0}, {0, 0, 1}},
+		},
+		Normalization: NormalizeBySum,
+	}
+	RabinerJuangTypeII = &StepPattern{
+		Paths: []LocalPath{
+			{{-2, -1, 0}, {-1, 0, 2}, {0, 0, 1}},
+			{{-1, -1, 0}, {0, 0, 2}},
+			{{-1, -2, 0}, {0, -1, 2}, {0, 0, 1}},
+		},
+		Normalization: NormalizeBySum,
+	}
+	RabinerJuangTypeIII = &StepPattern{
+		Paths: []LocalPath{
+			{{-2, -1, 0}, {0, 0, 3}},
+			{{-1, -1, 0}, {0, 0, 2}},
+			{{-1, -2, 0}, {0, 0, 3}},
+		},
+		Normalization: NormalizeBySum,
+	}
+	RabinerJuangTypeV = &StepPattern{
+		Paths: []LocalPath{
+			{{-3, -1, 0}, {-2, 0, 2}, {-1, 0, 1}, {0, 0, 1}},
+			{{-2, -1, 0}, {-1, 0, 2}, {0, 0, 1}},
+			{{-1, -1, 0}, {0, 0, 2}},
+			{{-1, -2, 0}, {0, -1, 2}, {0, 0, 1}},
+			{{-1, -3, 0}, {0, -2, 2}, {0, -1, 1}, {0, 0, 1}},
+		},
+		Normalization: NormalizeBySum,
+	}
+	RabinerJuangTypeVI = &StepPattern{
+		Paths: []LocalPath{
+			{{-3, -2, 0}, {-2, -1, 2}, {-1, 0, 2}, {0, 0, 1}},
+			{{-1, -1, 0}, {0, 0, 2}},
+			{{-2, -3, 0}, {-1, -2, 2}, {0, -1, 2}, {0, 0, 1}},
+		},
+		Normalization: NormalizeBySum,
+	}
+)
+
+// Align finds the minimum cost path from the first frames to the last
+// frames of the input sequence and the template under the step pattern
+// (Symmetric2 if Pattern is nil). Transition is added to the cost of each
+// local path only if it is not nil. It returns pairs of the sequence index
+// and the template index along the path, and the normalized cost. The
+// path is nil and the cost is +Inf if the last frames are unreachable.
+func (d *DTW) Align(sequence [][]float64) ([][2]int, float64) {
+	pattern := d.Pattern
+	if pattern == nil {
+		pattern = Symmetric2
+	}
+	n, m := len(sequence), len(d.Template)
+	if n == 0 || m == 0 {
+		return nil, math.Inf(1)
+	}
+
+	distance := make([][]float64, n)
+	cost := make([][]float64, n)
+	pointer := make([][]int, n)
+	for t := range sequence {
+		distance[t] = make([]float64, m)
+		cost[t] = make([]float64, m)
+		pointer[t] = make([]int, m)
+		for i := range d.Template {
+			distance[t][i] = d.ObservationCost(sequence[t], i)
+			cost[t][i] = math.Inf(1)
+			pointer[t][i] = -1
+		}
+	}
+	cost[0][0] = distance[0][0]
+
+	for t := 0; t < n; t++ {
+		for i := 0; i < m; i++ {
+			for k, path := range pattern.Paths {
+				t0, i0 := t+path[0].T, i+path[0].I
+				if t0 < 0 || i0 < 0 || math.IsInf(cost[t0][i0], 1) {
+					continue
+				}
+				c := cost[t0][i0]
+				for _, s := range path[1:] {
+					c += s.Weight * distance[t+s.T][i+s.I]
+				}
+				if d.Transition != nil {
+					c += d.Transition.Cost(i0, i)
+				}
+				if c < cost[t][i] {
+					cost[t][i], pointer[t][i] = c, k
+				}
+			}
+		}
+	}
+
+	if math.IsInf(cost[n-1][m-1], 1) {
+		return nil, math.Inf(1)
+	}
+
+	// Backtrace including intermediate points of local paths
+	path := [][2]int{{n - 1, m - 1}}
+	for t, i := n-1, m-1; pointer[t][i] >= 0; {
+		local := pattern.Paths[pointer[t][i]]
+		for k := len(local) - 2; k >= 0; k-- {
+			path = append(path, [2]int{t + local[k].T, i + local[k].I})
+		}
+		t, i = t+local[0].T, i+local[0].I
+	}
+	for k := 0; k < len(path)/2; k++ {
+		path[k], path[len(path)-1-k] = path[len(path)-1-k], path[k]
+	}
+
+	total := cost[n-1][m-1]
+	switch pattern.Normalization {
+	case NormalizeBySequence:
+		total /= float64(n)
+	case NormalizeByTemplate:
+		total /= float64(m)
+	case NormalizeBySum:
+		total /= float64(n + m)
+	}
+
+	return path, total
+}
+
+// templateIndices returns the template index for each frame of the input
+// sequence along the path. Frames skipped by the path take the index of
+// the following frame.
+func templateIndices(path [][2]int, length int) []int {
+	indices := make([]int, length)
+	for t := range indices {
+		indices[t] = -1
+	}
+	for _, p := range path {
+		indices[p[0]] = p[1]
+	}
+	for t := length - 2; t >= 0; t-- {
+		if indices[t] < 0 {
+			indices[t] = indices[t+1]
+		}
+	}
+	return indices
+}
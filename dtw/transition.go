@@ -0,0 +1,45 @@
+package dtw
+
+// TransitionCost computes the penalty of a transition from a template
+// index to another.
+type TransitionCost interface {
+	Cost(from, to int) float64
+}
+
+// TransitionCostFunc is an adapter to use an ordinary function as
+// TransitionCost.
+type TransitionCostFunc func(from, to int) float64
+
+// Cost returns f(from, to).
+func (f TransitionCostFunc) Cost(from, to int) float64 {
+	return f(from, to)
+}
+
+// PenaltyTransitionCost gives constant penalties to advancing to the next
+// template index, staying at the same index and others.
+type PenaltyTransitionCost struct {
+	Advance float64
+	Stay    float64
+	Other   float64
+}
+
+// DefaultTransitionCost returns the default transition cost of DTW.
+func DefaultTransitionCost() PenaltyTransitionCost {
+	return PenaltyTransitionCost{
+		Advance: 0.0,
+		Stay:    1.0,
+		Other:   2.0,
+	}
+}
+
+// Cost returns the penalty of the transition.
+func (p PenaltyTransitionCost) Cost(from, to int) float64 {
+	switch {
+	case to == from+1:
+		return p.Advance
+	case to == from:
+		return p.Stay
+	default:
+		return p.Other
+	}
+}
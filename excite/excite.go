@@ -2,4 +2,75 @@
 // fundamental frequency.
 package excite
 
-// TODO(ryuichi) use interface
+import (
+	"errors"
+	"math/rand"
+)
+
+// DefaultSeed is the seed of random number generators used unless Seed is
+// called.
+const DefaultSeed = 1
+
+var (
+	// ErrTooFewFrames is returned when frame-wise parameters given with f0
+	// sequence have fewer frames than the f0 sequence.
+	ErrTooFewFrames = errors.New("excite: fewer frames of parameters than f0")
+	// ErrSampleRateMismatch is returned when an f0 contour has a sample
+	// rate different from that of the excitation.
+	ErrSampleRateMismatch = errors.New("excite: sample rate of f0 contour mismatch")
+	// ErrFrameShiftMismatch is returned when an f0 contour has a frame
+	// shift different from that of the excitation.
+	ErrFrameShiftMismatch = errors.New("excite: frame shift of f0 contour mismatch")
+)
+
+// Source is the interface that excitation generators implement.
+// GenerateOneFrame keeps internal states between calls so that successive
+// frames are continuous, which enables streaming generation. Each source has
+// its own random number generator, so generation is reproducible and
+// different sources can be used concurrently.
+type Source interface {
+	// GenerateOneFrame generates an excitation signal of one frame whose f0
+	// is linearly interpolated from f01 at the beginning of the frame to f02
+	// at the end, i.e. f0 of the previous and the current frame.
+	GenerateOneFrame(f01, f02 float64) []float64
+
+	// Generate generates an excitation signal from f0 sequence. Internal
+	// states are reset before generation.
+	Generate(f0Sequence []float64) []float64
+
+	// Reset resets internal states except for random number generators.
+	Reset()
+
+	// Seed initializes the random number generator with a given seed.
+	Seed(seed int64)
+}
+
+// generate generates an excitation signal from f0 sequence by a frame-wise
+// generator.
+func generate(s Source, frameShift int, f0Sequence []float64) []float64 {
+	excite := make([]float64, frameShift*len(f0Sequence))
+
+	s.Reset()
+	if len(f0Sequence) == 0 {
+		return excite
+	}
+	previousF0 := f0Sequence[0]
+	for i, currentF0 := range f0Sequence {
+		if i > 0 {
+			previousF0 = f0Sequence[i-1]
+		}
+		exciteForFrame := s.GenerateOneFrame(previousF0, currentF0)
+		copy(excite[i*frameShift:], exciteForFrame)
+	}
+
+	return excite
+}
+
+// randomSource returns a random number generator, which is created with
+// DefaultSeed if r is nil.
+func randomSource(r **rand.Rand) *rand.Rand {
+	if *r == nil {
+		*r = rand.New(rand.NewSource(DefaultSeed))
+	}
+	return *r
+}
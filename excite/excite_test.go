@@ -1,6 +1,7 @@
 package excite
 
 import (
+	"github.com/r9y9/gossp/pitch"
 	"math"
 	"testing"
 )
@@ -97,3 +98,151 @@ func TestConsistencyBetweenGaussianOrNot(t *testing.T) {
 		}
 	}
 }
+
+// constantEstimator is a pitch.Estimator that gives a constant f0 contour.
+type constantEstimator struct {
+	f0         float64
+	sampleRate int
+	frameShift int
+}
+
+func (e constantEstimator) Estimate(audioBuffer []float64) *pitch.Contour {
+	f0Sequence := make([]float64, len(audioBuffer)/e.frameShift+1)
+	for i := range f0Sequence {
+		f0Sequence[i] = e.f0
+	}
+	return pitch.NewContour(f0Sequence, e.sampleRate, e.frameShift)
+}
+
+func TestGenerateFromEstimator(t *testing.T) {
+	sampleRate := 16000
+	frameShift := 80
+	ex := NewPulseExcite(sampleRate, frameShift)
+	audioBuffer := make([]float64, sampleRate/2)
+
+	estimator := constantEstimator{100.0, sampleRate, frameShift}
+	contour := estimator.Estimate(audioBuffer)
+	excitation, err := ex.GenerateFromEstimator(estimator, audioBuffer)
+	if err != nil {
+		t.Fatalf("GenerateFromEstimator returned error: %v", err)
+	}
+
+	expectedLen := frameShift * contour.Len()
+	if len(excitation) != expectedLen {
+		t.Errorf("The length of generated excitaiton is %d, want %d", len(excitation), expectedLen)
+	}
+
+	mismatched := constantEstimator{100.0, sampleRate, frameShift * 2}
+	if _, err := ex.GenerateFromEstimator(mismatched, audioBuffer); err != ErrFrameShiftMismatch {
+		t.Errorf("GenerateFromEstimator returned %v, want %v.", err, ErrFrameShiftMismatch)
+	}
+	mismatched = constantEstimator{100.0, sampleRate * 2, frameShift}
+	if _, err := ex.GenerateFromEstimator(mismatched, audioBuffer); err != ErrSampleRateMismatch {
+		t.Errorf("GenerateFromEstimator returned %v, want %v.", err, ErrSampleRateMismatch)
+	}
+}
+
+func TestSourceReproducibility(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	f0Sequence := []float64{0, 0, 100, 100, 110, 120, 0, 0, 0, 130, 130}
+
+	sources := map[string]func() Source{
+		"pulse":    func() Source { return NewPulseExcite(sampleRate, frameShift) },
+		"noise":    func() Source { return NewNoiseExcite(sampleRate, frameShift) },
+		"sinusoid": func() Source { return NewSinusoidExcite(sampleRate, frameShift) },
+	}
+
+	for name, newSource := range sources {
+		s1, s2 := newSource(), newSource()
+		s1.Seed(98765)
+		s2.Seed(98765)
+		excitation1 := s1.Generate(f0Sequence)
+		excitation2 := s2.Generate(f0Sequence)
+		for i := range excitation1 {
+			if excitation1[i] != excitation2[i] {
+				t.Errorf("%s: Index %d, excitation with the same seed must be equal. %f != %f",
+					name, i, excitation1[i], excitation2[i])
+				break
+			}
+		}
+	}
+}
+
+func TestSourceStreaming(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	f0Sequence := []float64{100, 100, 110, 120, 0, 0, 130, 130}
+
+	for _, s := range []Source{
+		NewPulseExcite(sampleRate, frameShift),
+		NewSinusoidExcite(sampleRate, frameShift),
+	} {
+		s.Seed(0)
+		batch := s.Generate(f0Sequence)
+
+		// frame-by-frame generation must be equal to batch generation
+		s.Seed(0)
+		s.Reset()
+		for i := range f0Sequence {
+			previousF0 := f0Sequence[0]
+			if i > 0 {
+				previousF0 = f0Sequence[i-1]
+			}
+			frame := s.GenerateOneFrame(previousF0, f0Sequence[i])
+			for j, val := range frame {
+				if val != batch[i*frameShift+j] {
+					t.Errorf("Frame %d, index %d, %f, want %f.",
+						i, j, val, batch[i*frameShift+j])
+				}
+			}
+		}
+	}
+}
+
+func TestSinusoidExcite(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	ex := NewSinusoidExcite(sampleRate, frameShift)
+
+	f0Sequence := make([]float64, 50)
+	for i := range f0Sequence {
+		f0Sequence[i] = 200.0
+	}
+	excitation := ex.Generate(f0Sequence)
+
+	// phase-continuous sinusoid with unit power
+	tolerance := 1.0e-9
+	for i, val := range excitation {
+		expected := math.Sqrt2 * math.Sin(2.0*math.Pi*200.0*float64(i)/float64(sampleRate))
+		if math.Abs(val-expected) > tolerance {
+			t.Errorf("Index %d, %f, want %f.", i, val, expected)
+			break
+		}
+	}
+}
+
+// upwardZeroCrossings returns indices where the signal crosses zero upward.
+func upwardZeroCrossings(x []float64) []int {
+	crossings := []int{}
+	for i := 1; i < len(x); i++ {
+		if x[i-1] < 0.0 && x[i] >= 0.0 {
+			crossings = append(crossings, i)
+		}
+	}
+	return crossings
+}
+
+func TestSinusoidExciteSweep(t *testing.T) {
+	sampleRate, frameShift := 16000, 400
+	ex := NewSinusoidExcite(sampleRate, frameShift)
+
+	// Rising f0 must give monotonically decreasing periods
+	f0Sequence := []float64{100, 150, 200, 250, 300, 350}
+	crossings := upwardZeroCrossings(ex.Generate(f0Sequence))
+	for k := 2; k < len(crossings); k++ {
+		previous := crossings[k-1] - crossings[k-2]
+		current := crossings[k] - crossings[k-1]
+		if current > previous+1 {
+			t.Errorf("Period %d samples at index %d, want less than %d.",
+				current, crossings[k], previous+1)
+		}
+	}
+}
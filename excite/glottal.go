@@ -0,0 +1,314 @@
+package excite
+
+// References:
+// [1] G. Fant, J. Liljencrants and Q. Lin, "A four-parameter model of
+// glottal flow," STL-QPSR, vol. 26, no. 4, pp.1-13, 1985.
+// [2] G. Fant, "The LF-model revisited. Transformations and frequency
+// domain analysis," STL-QPSR, vol. 36, no. 2-3, pp.119-156, 1995.
+// [3] A. E. Rosenberg, "Effect of glottal pulse shape on the quality of
+// natural vowels," J. Acoust. Soc. Am., vol. 49, pp.583-590, 1971.
+// [4] D. H. Klatt and L. C. Klatt, "Analysis, synthesis, and perception of
+// voice quality variations among female and male talkers," J. Acoust. Soc.
+// Am., vol. 87, pp.820-857, 1990.
+
+import (
+	"math"
+	"math/rand"
+)
+
+// GlottalModel specifies a glottal flow derivative model.
+type GlottalModel int
+
+const (
+	LFModel        GlottalModel = iota // Liljencrants-Fant model
+	RosenbergModel                     // Rosenberg (trigonometric) model
+	KLGLOTT88Model                     // KLGLOTT88 (polynomial) model
+)
+
+// GlottalParams represents the parameters of glottal flow models.
+type GlottalParams struct {
+	Oq   float64 // Open quotient (Rosenberg and KLGLOTT88)
+	Rd   float64 // Shape parameter (LF), from 0.3 (tense) to 2.7 (lax)
+	Tilt float64 // Additional spectral tilt in dB at 3 kHz
+}
+
+// DefaultGlottalParams is the parameters for modal voice.
+var DefaultGlottalParams = GlottalParams{Oq: 0.6, Rd: 1.0, Tilt: 0.0}
+
+const (
+	// Frequency where the spectral tilt is specified
+	tiltFrequency = 3000.0
+
+	// Ratio of the opening phase to the open phase of the Rosenberg model
+	rosenbergOpeningRatio = 0.7
+
+	// Number of points to compute the energy of glottal pulses
+	glottalEnergyPoints = 1000
+)
+
+// GlottalExcite represents generating excitation signals based on glottal
+// flow derivative models. Glottal parameters are linearly interpolated
+// between successive frames as with f0, and updated at the beginning of
+// each glottal period. Each period is normalized so that the power is 1 as
+// with PulseExcite. Gaussian noise is generated for unvoiced segments.
+type GlottalExcite struct {
+	SampleRate int
+	FrameShift int
+	Model      GlottalModel
+	Params     GlottalParams // Used in Generate and GenerateOneFrame
+
+	// Phase in the current glottal period, which ranges in [0, 1)
+	phase float64
+	// Waveform of the current glottal period as a function of phase
+	pulse func(float64) float64
+	// Previous output of the spectral tilt filter
+	tiltState float64
+
+	rand *rand.Rand
+}
+
+// NewGlottalExcite returns its instance with sample rate, frameshift and
+// glottal model. DefaultGlottalParams is used as the parameters.
+func NewGlottalExcite(sampleRate, frameShift int,
+	model GlottalModel) *GlottalExcite {
+	return &GlottalExcite{
+		SampleRate: sampleRate,
+		FrameShift: frameShift,
+		Model:      model,
+		Params:     DefaultGlottalParams,
+		rand:       rand.New(rand.NewSource(DefaultSeed)),
+	}
+}
+
+// Reset resets the glottal period and the state of the tilt filter.
+func (e *GlottalExcite) Reset() {
+	e.phase = 0.0
+	e.pulse = nil
+	e.tiltState = 0.0
+}
+
+// Seed initializes the random number generator with a given seed.
+func (e *GlottalExcite) Seed(seed int64) {
+	e.rand = rand.New(rand.NewSource(seed))
+}
+
+// Generate generates an excitation signal from f0 sequence with Params.
+func (e *GlottalExcite) Generate(f0Sequence []float64) []float64 {
+	return generate(e, e.FrameShift, f0Sequence)
+}
+
+// GenerateWithParams generates an excitation signal from f0 sequence and
+// glottal parameters of each frame. It returns ErrTooFewFrames if params
+// has fewer frames than f0Sequence.
+func (e *GlottalExcite) GenerateWithParams(f0Sequence []float64,
+	params []GlottalParams) ([]float64, error) {
+	if len(params) < len(f0Sequence) {
+		return nil, ErrTooFewFrames
+	}
+	excite := make([]float64, e.FrameShift*len(f0Sequence))
+
+	e.Reset()
+	if len(f0Sequence) == 0 {
+		return excite, nil
+	}
+	previousF0, previousParams := f0Sequence[0], params[0]
+	for i, currentF0 := range f0Sequence {
+		if i > 0 {
+			previousF0, previousParams = f0Sequence[i-1], params[i-1]
+		}
+		exciteForFrame := e.GenerateOneFrameWithParams(previousF0, currentF0,
+			previousParams, params[i])
+		copy(excite[i*e.FrameShift:], exciteForFrame)
+	}
+
+	return excite, nil
+}
+
+// GenerateOneFrame generates an excitation signal whose f0 is linearly
+// interpolated from f01 to f02 with Params.
+func (e *GlottalExcite) GenerateOneFrame(f01, f02 float64) []float64 {
+	return e.GenerateOneFrameWithParams(f01, f02, e.Params, e.Params)
+}
+
+// GenerateOneFrameWithParams generates an excitation signal whose f0 and
+// glottal parameters are linearly interpolated from f01 and p1 at the
+// beginning of the frame to f02 and p2 at the end. If the given f0 have
+// zero value(s), it generates gaussian noise.
+func (e *GlottalExcite) GenerateOneFrameWithParams(f01, f02 float64,
+	p1, p2 GlottalParams) []float64 {
+	excite := make([]float64, e.FrameShift)
+
+	if f01 == 0.0 || f02 == 0.0 {
+		e.Reset()
+		r := randomSource(&e.rand)
+		for i := range excite {
+			excite[i] = r.NormFloat64()
+		}
+		return excite
+	}
+
+	fs := float64(e.SampleRate)
+	for i := range excite {
+		// f0 and parameters are linearly interpolated
+		s := float64(i) / float64(e.FrameShift)
+		f0 := f01 + s*(f02-f01)
+		p := GlottalParams{
+			Oq:   p1.Oq + s*(p2.Oq-p1.Oq),
+			Rd:   p1.Rd + s*(p2.Rd-p1.Rd),
+			Tilt: p1.Tilt + s*(p2.Tilt-p1.Tilt),
+		}
+
+		if e.pulse == nil {
+			e.pulse = glottalPulse(e.Model, p)
+		}
+		val := e.pulse(e.phase)
+
+		// Spectral tilt by a first-order low-pass filter
+		a := tiltCoefficient(p.Tilt, fs)
+		e.tiltState = (1.0-a)*val + a*e.tiltState
+		excite[i] = e.tiltState
+
+		e.phase += f0 / fs
+		if e.phase >= 1.0 {
+			e.phase -= math.Floor(e.phase)
+			e.pulse = glottalPulse(e.Model, p)
+		}
+	}
+
+	return excite
+}
+
+// glottalPulse returns a glottal flow derivative waveform of one period as
+// a function of phase in [0, 1). The waveform is normalized to have unit
+// energy per period.
+func glottalPulse(model GlottalModel, p GlottalParams) func(float64) float64 {
+	var pulse func(float64) float64
+	switch model {
+	case RosenbergModel:
+		pulse = rosenbergPulse(p.Oq)
+	case KLGLOTT88Model:
+		pulse = klglott88Pulse(p.Oq)
+	default:
+		pulse = lfPulse(p.Rd)
+	}
+
+	energy := 0.0
+	for i := 0; i < glottalEnergyPoints; i++ {
+		val := pulse((float64(i) + 0.5) / glottalEnergyPoints)
+		energy += val * val
+	}
+	energy /= glottalEnergyPoints
+	if energy <= 0.0 {
+		return pulse
+	}
+	gain := 1.0 / math.Sqrt(energy)
+
+	return func(t float64) float64 {
+		return gain * pulse(t)
+	}
+}
+
+// rosenbergPulse returns the derivative of the Rosenberg glottal flow.
+func rosenbergPulse(oq float64) func(float64) float64 {
+	oq = clip(oq, 0.05, 1.0)
+	tp := rosenbergOpeningRatio * oq
+	tn := oq - tp
+
+	return func(t float64) float64 {
+		switch {
+		case t < tp:
+			return math.Pi / (2.0 * tp) * math.Sin(math.Pi*t/tp)
+		case t < oq:
+			return -math.Pi / (2.0 * tn) * math.Sin(math.Pi*(t-tp)/(2.0*tn))
+		default:
+			return 0.0
+		}
+	}
+}
+
+// klglott88Pulse returns the derivative of the KLGLOTT88 glottal flow.
+func klglott88Pulse(oq float64) func(float64) float64 {
+	oq = clip(oq, 0.05, 1.0)
+
+	return func(t float64) float64 {
+		if t >= oq {
+			return 0.0
+		}
+		tau := t / oq
+		return 2.0*tau - 3.0*tau*tau
+	}
+}
+
+// lfPulse returns the LF model waveform whose timing parameters are
+// determined by Rd [2].
+func lfPulse(rd float64) func(float64) float64 {
+	rd = clip(rd, 0.3, 2.7)
+
+	// Timing parameters normalized by the period
+	rap := (-1.0 + 4.8*rd) / 100.0
+	rkp := (22.4 + 11.8*rd) / 100.0
+	rgp := rkp / (4.0 * (0.11*rd/(0.5+1.2*rkp) - rap))
+	tp := 1.0 / (2.0 * rgp)
+	te := math.Min(tp*(1.0+rkp), 1.0-rap)
+	ta := rap
+	wg := math.Pi / tp
+
+	// Return phase: epsilon*ta = 1 - exp(-epsilon*(1-te))
+	epsilon := 1.0 / ta
+	for i := 0; i < 50; i++ {
+		epsilon = (1.0 - math.Exp(-epsilon*(1.0-te))) / ta
+	}
+	returnArea := -1.0 / (epsilon * ta) *
+		((1.0-math.Exp(-epsilon*(1.0-te)))/epsilon -
+			(1.0-te)*math.Exp(-epsilon*(1.0-te)))
+
+	// Open phase: the area of the whole period must be zero, which
+	// determines the growth factor alpha (Ee = 1).
+	openArea := func(alpha float64) float64 {
+		e0 := -1.0 / (math.Exp(alpha*te) * math.Sin(wg*te))
+		return e0 * (math.Exp(alpha*te)*(alpha*math.Sin(wg*te)-wg*math.Cos(wg*te)) +
+			wg) / (alpha*alpha + wg*wg)
+	}
+	// openArea decreases as alpha increases
+	low, high := -1.0, 1.0
+	for openArea(low)+returnArea < 0.0 && low > -1.0e+4 {
+		low *= 2.0
+	}
+	for openArea(high)+returnArea > 0.0 && high < 1.0e+4 {
+		high *= 2.0
+	}
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2.0
+		if openArea(mid)+returnArea > 0.0 {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	alpha := (low + high) / 2.0
+	e0 := -1.0 / (math.Exp(alpha*te) * math.Sin(wg*te))
+
+	return func(t float64) float64 {
+		if t <= te {
+			return e0 * math.Exp(alpha*t) * math.Sin(wg*t)
+		}
+		return -1.0 / (epsilon * ta) *
+			(math.Exp(-epsilon*(t-te)) - math.Exp(-epsilon*(1.0-te)))
+	}
+}
+
+// tiltCoefficient returns the coefficient of the first-order low-pass
+// filter (1-a)/(1-az^-1) that attenuates tilt dB at 3 kHz.
+func tiltCoefficient(tilt, sampleRate float64) float64 {
+	if tilt <= 0.0 {
+		return 0.0
+	}
+	w := 2.0 * math.Pi * math.Min(tiltFrequency, sampleRate/4.0) / sampleRate
+	g := math.Pow(10.0, -tilt/10.0)
+	b := 1.0 - g*math.Cos(w)
+	return (b - math.Sqrt(b*b-(1.0-g)*(1.0-g))) / (1.0 - g)
+}
+
+func clip(x, min, max float64) float64 {
+	return math.Max(min, math.Min(x, max))
+}
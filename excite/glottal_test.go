@@ -0,0 +1,182 @@
+package excite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGlottalPulse(t *testing.T) {
+	models := map[string]GlottalModel{
+		"LF":        LFModel,
+		"Rosenberg": RosenbergModel,
+		"KLGLOTT88": KLGLOTT88Model,
+	}
+	params := []GlottalParams{
+		{Oq: 0.4, Rd: 0.5},
+		DefaultGlottalParams,
+		{Oq: 0.8, Rd: 2.5},
+	}
+
+	const numPoints = 100000
+	for name, model := range models {
+		for _, p := range params {
+			pulse := glottalPulse(model, p)
+			area, energy := 0.0, 0.0
+			for i := 0; i < numPoints; i++ {
+				val := pulse((float64(i) + 0.5) / numPoints)
+				area += val
+				energy += val * val
+			}
+			area /= numPoints
+			energy /= numPoints
+
+			// Glottal flow must return to zero at the end of a period
+			if math.Abs(area) > 1.0e-3 {
+				t.Errorf("%s %v: area of flow derivative is %f, want zero.", name, p, area)
+			}
+			if math.Abs(energy-1.0) > 1.0e-3 {
+				t.Errorf("%s %v: energy per period is %f, want 1.", name, p, energy)
+			}
+		}
+	}
+}
+
+func TestLFOpenQuotient(t *testing.T) {
+	// Open phase (until the main excitation) gets longer as Rd increases
+	previousTe := 0.0
+	for _, rd := range []float64{0.3, 0.8, 1.5, 2.7} {
+		pulse := lfPulse(rd)
+		te, minimum := 0.0, 0.0
+		for i := 0; i < 10000; i++ {
+			phase := float64(i) / 10000.0
+			if val := pulse(phase); val < minimum {
+				te, minimum = phase, val
+			}
+		}
+		if te <= previousTe {
+			t.Errorf("Rd %f: main excitation at %f, want later than %f.", rd, te, previousTe)
+		}
+		previousTe = te
+	}
+}
+
+func TestGlottalExcite(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	f0Sequence := make([]float64, 100)
+	for i := range f0Sequence {
+		f0Sequence[i] = 100.0
+	}
+
+	for _, model := range []GlottalModel{LFModel, RosenbergModel, KLGLOTT88Model} {
+		ex := NewGlottalExcite(sampleRate, frameShift, model)
+		excitation := ex.Generate(f0Sequence)
+
+		if len(excitation) != frameShift*len(f0Sequence) {
+			t.Errorf("The length of generated excitaiton is %d, want %d",
+				len(excitation), frameShift*len(f0Sequence))
+		}
+
+		// Periodicity
+		period := sampleRate / 100
+		for i := period; i < len(excitation); i++ {
+			if math.Abs(excitation[i]-excitation[i-period]) > 1.0e-6 {
+				t.Errorf("Model %d: index %d, excitation must be periodic. %f != %f",
+					model, i, excitation[i], excitation[i-period])
+				break
+			}
+		}
+
+		// Unit power
+		power := 0.0
+		for _, val := range excitation {
+			power += val * val
+		}
+		power /= float64(len(excitation))
+		if math.Abs(power-1.0) > 0.05 {
+			t.Errorf("Model %d: power is %f, want about 1.", model, power)
+		}
+	}
+}
+
+func TestGlottalExciteTilt(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	f0Sequence := make([]float64, 50)
+	params := make([]GlottalParams, len(f0Sequence))
+	for i := range f0Sequence {
+		f0Sequence[i] = 120.0
+		params[i] = DefaultGlottalParams
+	}
+
+	ex := NewGlottalExcite(sampleRate, frameShift, LFModel)
+	flat, err := ex.GenerateWithParams(f0Sequence, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range params {
+		params[i].Tilt = 20.0
+	}
+	tilted, err := ex.GenerateWithParams(f0Sequence, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// High frequency energy measured by the first difference
+	highFrequencyEnergy := func(x []float64) float64 {
+		energy := 0.0
+		for i := 1; i < len(x); i++ {
+			energy += (x[i] - x[i-1]) * (x[i] - x[i-1])
+		}
+		return energy
+	}
+	if highFrequencyEnergy(tilted) >= highFrequencyEnergy(flat)/2.0 {
+		t.Errorf("Tilt of 20 dB must attenuate high frequency energy. %f, %f",
+			highFrequencyEnergy(tilted), highFrequencyEnergy(flat))
+	}
+
+	// Tilt coefficient gives the specified attenuation at 3 kHz
+	a := tiltCoefficient(20.0, float64(sampleRate))
+	w := 2.0 * math.Pi * tiltFrequency / float64(sampleRate)
+	gain := (1.0 - a) * (1.0 - a) / (1.0 - 2.0*a*math.Cos(w) + a*a)
+	if math.Abs(10.0*math.Log10(gain)+20.0) > 1.0e-6 {
+		t.Errorf("Attenuation at 3 kHz is %f dB, want -20 dB.", 10.0*math.Log10(gain))
+	}
+}
+
+func TestGlottalExciteWithParams(t *testing.T) {
+	sampleRate, frameShift := 16000, 400
+	ex := NewGlottalExcite(sampleRate, frameShift, KLGLOTT88Model)
+
+	f0Sequence := []float64{100, 150, 200, 250, 300, 350}
+	params := make([]GlottalParams, len(f0Sequence))
+	for i := range params {
+		params[i] = GlottalParams{Oq: 0.5}
+	}
+
+	_, err := ex.GenerateWithParams(f0Sequence, params[:3])
+	if err != ErrTooFewFrames {
+		t.Errorf("Error %v with too few parameters, want %v.", err,
+			ErrTooFewFrames)
+	}
+
+	excitation, err := ex.GenerateWithParams(f0Sequence, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rising f0 must give monotonically decreasing periods, where each
+	// period begins at the end of the closed phase
+	onsets := []int{}
+	for i := 1; i < len(excitation); i++ {
+		if excitation[i-1] == 0.0 && excitation[i] > 0.0 {
+			onsets = append(onsets, i)
+		}
+	}
+	for k := 2; k < len(onsets); k++ {
+		previous := onsets[k-1] - onsets[k-2]
+		current := onsets[k] - onsets[k-1]
+		if current > previous+1 {
+			t.Errorf("Period %d samples at index %d, want less than %d.",
+				current, onsets[k], previous+1)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package excite
+
+import (
+	"errors"
+	"github.com/r9y9/gossp/window"
+	"math"
+	"math/rand"
+)
+
+// DefaultMixedFilterLen is the default length of band-pass filters used to
+// divide pulse and noise components into bands.
+const DefaultMixedFilterLen = 255
+
+// DefaultBandEdges is the default boundaries between bands in Hz.
+var DefaultBandEdges = []float64{1000.0, 2000.0, 4000.0, 6000.0}
+
+// ErrInvalidNumBands is returned when band aperiodicity does not have
+// NumBands values.
+var ErrInvalidNumBands = errors.New("excite: number of bands of aperiodicity mismatch")
+
+// MixedExcite represents generating mixed excitation signals. The pulse
+// train and gaussian noise are divided into frequency bands and mixed in
+// each band according to band aperiodicity, which is the ratio of noise
+// power in the band (voicing strength is 1 - aperiodicity). Band
+// aperiodicity is linearly interpolated between successive frames as
+// with f0.
+//
+// Since the band-pass filters are linear-phase, frame-wise generation by
+// GenerateOneFrame is delayed by FilterLen/2 samples. Generate and
+// GenerateWithAperiodicity compensate the delay.
+type MixedExcite struct {
+	SampleRate int
+	FrameShift int
+	BandEdges  []float64 // Boundaries between bands in Hz
+	FilterLen  int       // Length of band-pass filters (odd)
+
+	// Band aperiodicity used in Generate and GenerateOneFrame
+	aperiodicity []float64
+
+	pulse   *PulseExcite
+	filters [][]float64
+	// Last FilterLen samples of pulse and noise components
+	pulseHistory []float64
+	noiseHistory []float64
+	// Band aperiodicity of the last FilterLen/2+1 samples (ring buffer),
+	// where apIndex is the position to be written next
+	apHistory [][]float64
+	apIndex   int
+
+	rand *rand.Rand
+}
+
+// NewMixedExcite returns its instance with sample rate, frameshift and
+// boundaries between bands. If bandEdges is nil, DefaultBandEdges is used.
+// Excitation is periodic in all bands until SetBandAperiodicity is called.
+func NewMixedExcite(sampleRate, frameShift int,
+	bandEdges []float64) *MixedExcite {
+	if bandEdges == nil {
+		bandEdges = DefaultBandEdges
+	}
+	return &MixedExcite{
+		SampleRate: sampleRate,
+		FrameShift: frameShift,
+		BandEdges:  bandEdges,
+		FilterLen:  DefaultMixedFilterLen,
+		pulse:      NewPulseExcite(sampleRate, frameShift),
+		rand:       rand.New(rand.NewSource(DefaultSeed)),
+	}
+}
+
+// Reset resets the pulse position and the states of band-pass filters.
+// Changes of BandEdges and FilterLen take effect after Reset.
+func (e *MixedExcite) Reset() {
+	e.pulse.Reset()
+	e.filters = nil
+	e.pulseHistory = nil
+	e.noiseHistory = nil
+	e.apHistory = nil
+	e.apIndex = 0
+}
+
+// Seed initializes the random number generator with a given seed.
+func (e *MixedExcite) Seed(seed int64) {
+	e.rand = rand.New(rand.NewSource(seed))
+}
+
+// NumBands returns the number of bands.
+func (e *MixedExcite) NumBands() int {
+	return len(e.BandEdges) + 1
+}
+
+// SetBandAperiodicity sets band aperiodicity used in Generate and
+// GenerateOneFrame, which must have NumBands() values in [0, 1].
+func (e *MixedExcite) SetBandAperiodicity(bandAperiodicity []float64) error {
+	if len(bandAperiodicity) != e.NumBands() {
+		return ErrInvalidNumBands
+	}
+	e.aperiodicity = make([]float64, len(bandAperiodicity))
+	copy(e.aperiodicity, bandAperiodicity)
+	return nil
+}
+
+// Generate generates a mixed excitation signal from f0 sequence with the
+// band aperiodicity set by SetBandAperiodicity.
+func (e *MixedExcite) Generate(f0Sequence []float64) []float64 {
+	bandAperiodicity := make([][]float64, len(f0Sequence))
+	for i := range bandAperiodicity {
+		bandAperiodicity[i] = e.bandAperiodicity()
+	}
+	excite, _ := e.GenerateWithAperiodicity(f0Sequence, bandAperiodicity)
+	return excite
+}
+
+// GenerateWithAperiodicity generates a mixed excitation signal from f0
+// sequence and band aperiodicity of each frame. bandAperiodicity[i] must
+// have NumBands() values in [0, 1]. Unvoiced frames (zero f0) are regarded
+// as aperiodic in all bands. It returns ErrTooFewFrames if
+// bandAperiodicity has fewer frames than f0Sequence.
+func (e *MixedExcite) GenerateWithAperiodicity(f0Sequence []float64,
+	bandAperiodicity [][]float64) ([]float64, error) {
+	if len(bandAperiodicity) < len(f0Sequence) {
+		return nil, ErrTooFewFrames
+	}
+	for _, ap := range bandAperiodicity[:len(f0Sequence)] {
+		if len(ap) != e.NumBands() {
+			return nil, ErrInvalidNumBands
+		}
+	}
+
+	e.Reset()
+	length := e.FrameShift * len(f0Sequence)
+	if length == 0 {
+		return make([]float64, 0), nil
+	}
+
+	delay := e.FilterLen / 2
+	excite := make([]float64, 0, length+delay)
+	previousF0, previousAp := f0Sequence[0], bandAperiodicity[0]
+	for i, currentF0 := range f0Sequence {
+		if i > 0 {
+			previousF0, previousAp = f0Sequence[i-1], bandAperiodicity[i-1]
+		}
+		excite = append(excite, e.generateOneFrame(previousF0, currentF0,
+			previousAp, bandAperiodicity[i])...)
+	}
+
+	// Flush the band-pass filters to compensate the delay
+	r := randomSource(&e.rand)
+	for n := 0; n < delay; n++ {
+		excite = append(excite, e.mix(0.0, r.NormFloat64(), nil))
+	}
+
+	return excite[delay:], nil
+}
+
+// GenerateOneFrame generates a mixed excitation signal whose f0 is linearly
+// interpolated from f01 to f02 with the band aperiodicity set by
+// SetBandAperiodicity. The output is delayed by FilterLen/2 samples.
+func (e *MixedExcite) GenerateOneFrame(f01, f02 float64) []float64 {
+	ap := e.bandAperiodicity()
+	return e.generateOneFrame(f01, f02, ap, ap)
+}
+
+// GenerateOneFrameWithAperiodicity generates a mixed excitation signal
+// whose f0 and band aperiodicity are linearly interpolated from f01 and ap1
+// at the beginning of the frame to f02 and ap2 at the end. The output is
+// delayed by FilterLen/2 samples.
+func (e *MixedExcite) GenerateOneFrameWithAperiodicity(f01, f02 float64,
+	ap1, ap2 []float64) ([]float64, error) {
+	if len(ap1) != e.NumBands() || len(ap2) != e.NumBands() {
+		return nil, ErrInvalidNumBands
+	}
+	return e.generateOneFrame(f01, f02, ap1, ap2), nil
+}
+
+func (e *MixedExcite) generateOneFrame(f01, f02 float64,
+	ap1, ap2 []float64) []float64 {
+	excite := make([]float64, e.FrameShift)
+
+	// Pulses are not generated at the boundary of voiced segments
+	pulse := e.pulse.GenerateOneFrame(f01, f02)
+	unvoiced := f01 == 0.0 || f02 == 0.0
+
+	r := randomSource(&e.rand)
+	ap := make([]float64, e.NumBands())
+	for j := range excite {
+		s := float64(j) / float64(e.FrameShift)
+		p := pulse[j]
+		for b := range ap {
+			ap[b] = ap1[b] + s*(ap2[b]-ap1[b])
+			if unvoiced {
+				ap[b] = 1.0
+			}
+		}
+		if unvoiced {
+			p = 0.0
+		}
+		excite[j] = e.mix(p, r.NormFloat64(), ap)
+	}
+
+	return excite
+}
+
+// mix filters pulse and noise samples by the band-pass filters, and returns
+// the mixed sample delayed by FilterLen/2 samples. If ap is nil, the band
+// aperiodicity of the previous sample is kept.
+func (e *MixedExcite) mix(pulse, noise float64, ap []float64) float64 {
+	if e.filters == nil {
+		e.initializeFilters()
+	}
+	last := e.FilterLen - 1
+	copy(e.pulseHistory, e.pulseHistory[1:])
+	copy(e.noiseHistory, e.noiseHistory[1:])
+	e.pulseHistory[last], e.noiseHistory[last] = pulse, noise
+
+	size := len(e.apHistory)
+	if ap == nil {
+		ap = e.apHistory[(e.apIndex+size-1)%size]
+	}
+	copy(e.apHistory[e.apIndex], ap)
+	e.apIndex = (e.apIndex + 1) % size
+	delayedAp := e.apHistory[e.apIndex]
+
+	mixed := 0.0
+	for b, filter := range e.filters {
+		p, q := 0.0, 0.0
+		for k, h := range filter {
+			p += h * e.pulseHistory[last-k]
+			q += h * e.noiseHistory[last-k]
+		}
+		a := math.Max(0.0, math.Min(delayedAp[b], 1.0))
+		mixed += math.Sqrt(1.0-a)*p + math.Sqrt(a)*q
+	}
+
+	return mixed
+}
+
+// initializeFilters creates band-pass filters and their states. The noise
+// component before the first sample is filled with gaussian noise so that
+// the noise level is stationary from the beginning.
+func (e *MixedExcite) initializeFilters() {
+	e.filters = e.bandFilters()
+	e.pulseHistory = make([]float64, e.FilterLen)
+	e.noiseHistory = make([]float64, e.FilterLen)
+	r := randomSource(&e.rand)
+	for k := range e.noiseHistory {
+		e.noiseHistory[k] = r.NormFloat64()
+	}
+	e.apHistory = make([][]float64, e.FilterLen/2+1)
+	for k := range e.apHistory {
+		e.apHistory[k] = make([]float64, e.NumBands())
+	}
+	e.apIndex = 0
+}
+
+// bandAperiodicity returns the band aperiodicity set by
+// SetBandAperiodicity, or zeros if it is not set or the number of bands
+// has been changed.
+func (e *MixedExcite) bandAperiodicity() []float64 {
+	if len(e.aperiodicity) != e.NumBands() {
+		return make([]float64, e.NumBands())
+	}
+	return e.aperiodicity
+}
+
+// bandFilters returns linear-phase band-pass filters whose sum is a
+// delayed impulse, which means that the bands perfectly cover the whole
+// frequency range.
+func (e *MixedExcite) bandFilters() [][]float64 {
+	numBands := e.NumBands()
+	filters := make([][]float64, numBands)
+
+	lowPass := func(cutoff float64) []float64 {
+		h := make([]float64, e.FilterLen)
+		w := window.CreateBlackman(e.FilterLen)
+		fc := cutoff / float64(e.SampleRate)
+		for k := range h {
+			n := float64(k - e.FilterLen/2)
+			if n == 0.0 {
+				h[k] = 2.0 * fc
+			} else {
+				h[k] = math.Sin(2.0*math.Pi*fc*n) / (math.Pi * n)
+			}
+			h[k] *= w[k]
+		}
+		return h
+	}
+
+	previous := make([]float64, e.FilterLen)
+	for b := 0; b < numBands; b++ {
+		var current []float64
+		if b < numBands-1 {
+			current = lowPass(e.BandEdges[b])
+		} else {
+			// all-pass (impulse)
+			current = make([]float64, e.FilterLen)
+			current[e.FilterLen/2] = 1.0
+		}
+		filters[b] = make([]float64, e.FilterLen)
+		for k := range current {
+			filters[b][k] = current[k] - previous[k]
+		}
+		previous = current
+	}
+
+	return filters
+}
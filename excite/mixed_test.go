@@ -0,0 +1,142 @@
+package excite
+
+import (
+	"math"
+	"testing"
+)
+
+func createBandAperiodicity(numFrames, numBands int, ap float64) [][]float64 {
+	bandAperiodicity := make([][]float64, numFrames)
+	for i := range bandAperiodicity {
+		bandAperiodicity[i] = make([]float64, numBands)
+		for b := range bandAperiodicity[i] {
+			bandAperiodicity[i][b] = ap
+		}
+	}
+	return bandAperiodicity
+}
+
+func TestMixedExciteLength(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	ex := NewMixedExcite(sampleRate, frameShift, nil)
+
+	f0Sequence := []float64{100, 100, 99, 98, 97, 0, 0, 0, 70, 70, 71}
+	bandAperiodicity := createBandAperiodicity(len(f0Sequence), ex.NumBands(), 0.5)
+
+	excitation, err := ex.GenerateWithAperiodicity(f0Sequence, bandAperiodicity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedLen := frameShift * len(f0Sequence)
+	if len(excitation) != expectedLen {
+		t.Errorf("The length of generated excitaiton is %d, want %d", len(excitation), expectedLen)
+	}
+}
+
+// Mixed excitation without aperiodicity must be the pulse train, since the
+// band-pass filters sum up to an impulse.
+func TestMixedExciteWithoutAperiodicity(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	ex := NewMixedExcite(sampleRate, frameShift, nil)
+
+	f0Sequence := []float64{100, 100, 110, 120, 130, 140, 150, 150, 150}
+	bandAperiodicity := createBandAperiodicity(len(f0Sequence), ex.NumBands(), 0.0)
+
+	mixed, err := ex.GenerateWithAperiodicity(f0Sequence, bandAperiodicity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulse := NewPulseExcite(sampleRate, frameShift).Generate(f0Sequence)
+
+	tolerance := 1.0e-10
+	for i := range pulse {
+		if math.Abs(mixed[i]-pulse[i]) > tolerance {
+			t.Errorf("Index %d, %f, want %f.", i, mixed[i], pulse[i])
+		}
+	}
+}
+
+func TestMixedExciteUnvoiced(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	ex := NewMixedExcite(sampleRate, frameShift, nil)
+
+	f0Sequence := make([]float64, 100)
+	bandAperiodicity := createBandAperiodicity(len(f0Sequence), ex.NumBands(), 0.0)
+
+	excitation, err := ex.GenerateWithAperiodicity(f0Sequence, bandAperiodicity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unvoiced excitation is gaussian noise with unit power
+	power := 0.0
+	for _, val := range excitation {
+		power += val * val
+	}
+	power /= float64(len(excitation))
+	if math.Abs(power-1.0) > 0.1 {
+		t.Errorf("Power of unvoiced excitation is %f, want about 1.", power)
+	}
+}
+
+func TestMixedExciteInvalidInput(t *testing.T) {
+	ex := NewMixedExcite(16000, 80, nil)
+
+	excitation, err := ex.GenerateWithAperiodicity([]float64{}, nil)
+	if err != nil || len(excitation) != 0 {
+		t.Errorf("Empty f0 gives %v and %v, want empty excitation.",
+			excitation, err)
+	}
+
+	f0Sequence := []float64{100, 100, 100}
+	bandAperiodicity := createBandAperiodicity(2, ex.NumBands(), 0.5)
+	_, err = ex.GenerateWithAperiodicity(f0Sequence, bandAperiodicity)
+	if err != ErrTooFewFrames {
+		t.Errorf("Error %v, want %v.", err, ErrTooFewFrames)
+	}
+
+	bandAperiodicity = createBandAperiodicity(3, ex.NumBands()-1, 0.5)
+	_, err = ex.GenerateWithAperiodicity(f0Sequence, bandAperiodicity)
+	if err != ErrInvalidNumBands {
+		t.Errorf("Error %v, want %v.", err, ErrInvalidNumBands)
+	}
+	if err := ex.SetBandAperiodicity([]float64{0.5}); err != ErrInvalidNumBands {
+		t.Errorf("Error %v, want %v.", err, ErrInvalidNumBands)
+	}
+}
+
+// Frame-wise generation must be equal to batch generation delayed by half
+// the filter length.
+func TestMixedExciteStreaming(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	var s Source = NewMixedExcite(sampleRate, frameShift, nil)
+	ex := s.(*MixedExcite)
+	err := ex.SetBandAperiodicity([]float64{0.0, 0.1, 0.3, 0.6, 0.9})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f0Sequence := []float64{100, 100, 110, 120, 0, 0, 130, 130, 130, 130}
+	batch := s.Generate(f0Sequence)
+
+	s.Seed(DefaultSeed)
+	s.Reset()
+	streaming := []float64{}
+	for i := range f0Sequence {
+		previousF0 := f0Sequence[0]
+		if i > 0 {
+			previousF0 = f0Sequence[i-1]
+		}
+		streaming = append(streaming, s.GenerateOneFrame(previousF0,
+			f0Sequence[i])...)
+	}
+
+	delay := ex.FilterLen / 2
+	for i := delay; i < len(streaming); i++ {
+		if math.Abs(streaming[i]-batch[i-delay]) > 1.0e-12 {
+			t.Errorf("Index %d, %f, want %f.", i, streaming[i], batch[i-delay])
+			break
+		}
+	}
+}
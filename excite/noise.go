@@ -0,0 +1,49 @@
+package excite
+
+import (
+	"math/rand"
+)
+
+// NoiseExcite represents generating gaussian noise excitation signals
+// regardless of f0, which is typically used for whispered speech or
+// unvoiced sounds.
+type NoiseExcite struct {
+	SampleRate int
+	FrameShift int
+
+	rand *rand.Rand
+}
+
+// NewNoiseExcite returns its instance with sample rate and frameshift.
+func NewNoiseExcite(sampleRate, frameShift int) *NoiseExcite {
+	return &NoiseExcite{
+		SampleRate: sampleRate,
+		FrameShift: frameShift,
+		rand:       rand.New(rand.NewSource(DefaultSeed)),
+	}
+}
+
+// Reset does nothing since noise excitation has no internal states.
+func (e *NoiseExcite) Reset() {}
+
+// Seed initializes the random number generator with a given seed.
+func (e *NoiseExcite) Seed(seed int64) {
+	e.rand = rand.New(rand.NewSource(seed))
+}
+
+// Generate generates a gaussian noise excitation signal whose length is
+// FrameShift*len(f0Sequence).
+func (e *NoiseExcite) Generate(f0Sequence []float64) []float64 {
+	return generate(e, e.FrameShift, f0Sequence)
+}
+
+// GenerateOneFrame generates gaussian noise of one frame. Given f0 are
+// ignored.
+func (e *NoiseExcite) GenerateOneFrame(f01, f02 float64) []float64 {
+	excite := make([]float64, e.FrameShift)
+	r := randomSource(&e.rand)
+	for i := range excite {
+		excite[i] = r.NormFloat64()
+	}
+	return excite
+}
@@ -1,6 +1,7 @@
 package excite
 
 import (
+	"github.com/r9y9/gossp/pitch"
 	"math"
 	"math/rand"
 )
@@ -16,33 +17,50 @@ type PulseExcite struct {
 	// Number of samples after pulse generated is used to keep continuity
 	// of successive two frames.
 	numSamplesAfterPulseGenerated int
+
+	rand *rand.Rand
 }
 
 // NewPulseExcite returns its instance with sample rate and framshift.
 func NewPulseExcite(sampleRate, frameShift int) *PulseExcite {
-	return &PulseExcite{sampleRate, frameShift, false, 0}
+	return &PulseExcite{
+		SampleRate: sampleRate,
+		FrameShift: frameShift,
+		rand:       rand.New(rand.NewSource(DefaultSeed)),
+	}
+}
+
+// Reset resets the pulse position.
+func (e *PulseExcite) Reset() {
+	e.numSamplesAfterPulseGenerated = 0
+}
+
+// Seed initializes the random number generator with a given seed.
+func (e *PulseExcite) Seed(seed int64) {
+	e.rand = rand.New(rand.NewSource(seed))
 }
 
 // Gerenate generates an excitation signal from f0 sequence. If the
 // unvoiced segment is detected (segment of zero f0), this generates
 // gaussian or pseudo random samples, f0-dependent excitation otherwise.
 func (e *PulseExcite) Generate(f0Sequence []float64) []float64 {
-	excite := make([]float64, e.FrameShift*len(f0Sequence))
+	return generate(e, e.FrameShift, f0Sequence)
+}
 
-	// reset
-	e.numSamplesAfterPulseGenerated = 0
-	previousF0 := f0Sequence[0]
-	for i, currentF0 := range f0Sequence {
-		if i > 0 {
-			previousF0 = f0Sequence[i-1]
-		}
-		exciteForFrame := e.GenerateOneFrame(currentF0, previousF0)
-		for j := 0; j < e.FrameShift; j++ {
-			excite[i*e.FrameShift+j] = exciteForFrame[j]
-		}
+// GenerateFromEstimator generates an excitation signal from the f0 contour
+// that the estimator estimates for a given audio buffer. It returns
+// ErrSampleRateMismatch or ErrFrameShiftMismatch if the contour does not
+// have the same sample rate and frame shift as the excitation.
+func (e *PulseExcite) GenerateFromEstimator(estimator pitch.Estimator,
+	audioBuffer []float64) ([]float64, error) {
+	contour := estimator.Estimate(audioBuffer)
+	if contour.SampleRate != e.SampleRate {
+		return nil, ErrSampleRateMismatch
 	}
-
-	return excite
+	if contour.FrameShift != e.FrameShift {
+		return nil, ErrFrameShiftMismatch
+	}
+	return e.Generate(contour.F0), nil
 }
 
 // GenerateOneFrame generates an excitation signal from successive two f0.
@@ -54,11 +72,12 @@ func (e *PulseExcite) GenerateOneFrame(f01, f02 float64) []float64 {
 	// Generate random samples
 	if f01 == 0.0 || f02 == 0.0 {
 		e.numSamplesAfterPulseGenerated = 0
+		r := randomSource(&e.rand)
 		for i := range excite {
 			if e.UseGauss {
-				excite[i] = rand.NormFloat64()
+				excite[i] = r.NormFloat64()
 			} else {
-				excite[i] = rand.Float64()
+				excite[i] = r.Float64()
 			}
 		}
 		return excite
@@ -0,0 +1,72 @@
+package excite
+
+import (
+	"math"
+	"math/rand"
+)
+
+// SinusoidExcite represents generating sinusoidal excitation signals whose
+// frequency follows f0. The phase is kept continuous between successive
+// frames, and the amplitude is normalized so that the power is 1 as with
+// PulseExcite. Gaussian noise is generated for unvoiced segments.
+type SinusoidExcite struct {
+	SampleRate int
+	FrameShift int
+
+	// Phase in radians at the beginning of the next frame
+	phase float64
+
+	rand *rand.Rand
+}
+
+// NewSinusoidExcite returns its instance with sample rate and frameshift.
+func NewSinusoidExcite(sampleRate, frameShift int) *SinusoidExcite {
+	return &SinusoidExcite{
+		SampleRate: sampleRate,
+		FrameShift: frameShift,
+		rand:       rand.New(rand.NewSource(DefaultSeed)),
+	}
+}
+
+// Reset resets the phase.
+func (e *SinusoidExcite) Reset() {
+	e.phase = 0.0
+}
+
+// Seed initializes the random number generator with a given seed.
+func (e *SinusoidExcite) Seed(seed int64) {
+	e.rand = rand.New(rand.NewSource(seed))
+}
+
+// Generate generates a sinusoidal excitation signal from f0 sequence.
+func (e *SinusoidExcite) Generate(f0Sequence []float64) []float64 {
+	return generate(e, e.FrameShift, f0Sequence)
+}
+
+// GenerateOneFrame generates a sinusoidal excitation signal whose frequency
+// is linearly interpolated from f01 to f02 in the frame, so that the
+// instantaneous frequency is continuous when f0 of the previous and the
+// current frame are given. If the given f0 have zero value(s),
+// GenerateOneFrame generates gaussian noise.
+func (e *SinusoidExcite) GenerateOneFrame(f01, f02 float64) []float64 {
+	excite := make([]float64, e.FrameShift)
+
+	if f01 == 0.0 || f02 == 0.0 {
+		r := randomSource(&e.rand)
+		for i := range excite {
+			excite[i] = r.NormFloat64()
+		}
+		return excite
+	}
+
+	slope := (f02 - f01) / float64(e.FrameShift)
+	for i := range excite {
+		excite[i] = math.Sqrt2 * math.Sin(e.phase)
+
+		linearlyInterpolatedF0 := f01 + slope*float64(i)
+		e.phase += 2.0 * math.Pi * linearlyInterpolatedF0 / float64(e.SampleRate)
+	}
+	e.phase = math.Mod(e.phase, 2.0*math.Pi)
+
+	return excite
+}
@@ -0,0 +1,76 @@
+package f0
+
+// Reference:
+// M. Morise, H. Kawahara and H. Katayose, "Fast and reliable F0 estimation
+// method based on the period extraction of vocal fold vibration of singing
+// voice and speech," Proc. AES 35th International Conference, 2009.
+
+import (
+	"github.com/r9y9/gossp/window"
+	"math"
+)
+
+const (
+	DIOChannelsInOctave = 2.0
+	DIOAllowedRange     = 0.1
+)
+
+// DIO estimates an f0 sequence by the Distributed Inline-filter Operation
+// (DIO) used in WORLD. The n-th element corresponds to the time
+// n*frameShift and f0 is set to zero for unvoiced frames. The estimate is
+// rough and should be refined by StoneMask. An empty audio buffer gives a
+// single unvoiced frame.
+func DIO(audioBuffer []float64, sampleRate int, frameShift int,
+	min, max float64) []float64 {
+	fs := float64(sampleRate)
+	numFrames := numF0Frames(len(audioBuffer), frameShift)
+	if len(audioBuffer) == 0 {
+		// a single unvoiced frame
+		return make([]float64, numFrames)
+	}
+	positions := make([]float64, numFrames)
+	for i := range positions {
+		positions[i] = float64(i * frameShift)
+	}
+
+	maxHalfAverageLength := int(fs/min/2.0 + 0.5)
+	fftSize := nextPow2(len(audioBuffer) + 4*maxHalfAverageLength +
+		2*int(fs/lowCutFrequency+0.5) + 2)
+	spectrum := spectrumForEstimation(audioBuffer, sampleRate, fftSize)
+
+	bestF0 := make([]float64, numFrames)
+	bestScore := make([]float64, numFrames)
+	for i := range bestScore {
+		bestScore[i] = maximumValue
+	}
+	candidates := make([][]float64, numFrames)
+
+	numBands := 1 + int(math.Log2(max/min)*DIOChannelsInOctave)
+	for i := 0; i < numBands; i++ {
+		boundaryF0 := min * math.Pow(2.0, float64(i+1)/DIOChannelsInOctave)
+		halfAverageLength := int(fs/boundaryF0/2.0 + 0.5)
+
+		// Low-pass filtering by the Nuttall window
+		filter := window.CreateNuttall(4*halfAverageLength + 1)
+		filtered := filteredSignal(spectrum, filter, len(audioBuffer))
+
+		candidate, deviation := zeroCrossingF0(filtered, sampleRate, positions)
+		if candidate == nil {
+			continue
+		}
+		for j, f := range candidate {
+			if f > boundaryF0 || f < boundaryF0/2.0 || f > max || f < min {
+				continue
+			}
+			candidates[j] = append(candidates[j], f)
+			score := deviation[j] / (f + safeGuardMinimum)
+			if score < bestScore[j] {
+				bestF0[j], bestScore[j] = f, score
+			}
+		}
+	}
+
+	voiceRangeMinimum := int(0.5+fs/float64(frameShift)/min)*2 + 1
+	return fixF0Contour(bestF0, candidates, voiceRangeMinimum,
+		DIOAllowedRange)
+}
@@ -0,0 +1,116 @@
+package f0
+
+import (
+	"github.com/r9y9/gossp/pitch"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// createHarmonics returns a signal composed of harmonics of freq with
+// amplitudes decaying as 1/k.
+func createHarmonics(freq float64, sampleRate, length int) []float64 {
+	x := make([]float64, length)
+	for k := 1; float64(k)*freq < float64(sampleRate)/2.0; k++ {
+		for i := range x {
+			x[i] += math.Sin(2.0*math.Pi*freq*float64(k)*float64(i)/
+				float64(sampleRate)) / float64(k)
+		}
+	}
+	return x
+}
+
+func testWORLDEstimatorBase(t *testing.T, name string,
+	estimate func([]float64, int, int, float64, float64) []float64,
+	x []float64, freq float64, sampleRate, frameShift int,
+	toleranceInHz float64) {
+	f0Sequence := estimate(x, sampleRate, frameShift, 60.0, 700.0)
+
+	if len(f0Sequence) != len(x)/frameShift+1 {
+		t.Errorf("%s: The number of frames is %d, want %d.",
+			name, len(f0Sequence), len(x)/frameShift+1)
+	}
+	// skip frames near edges
+	for i := 10; i < len(f0Sequence)-10; i++ {
+		if math.Abs(f0Sequence[i]-freq) > toleranceInHz {
+			t.Errorf("%s: The estimate is %f of %f Hz signal at frame %d, want within %f Hz.",
+				name, f0Sequence[i], freq, i, toleranceInHz)
+		}
+	}
+}
+
+func dioWithStoneMask(x []float64, sampleRate, frameShift int,
+	min, max float64) []float64 {
+	return StoneMask(x, sampleRate, frameShift,
+		DIO(x, sampleRate, frameShift, min, max))
+}
+
+func TestDIO(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	freqSet := []float64{100.0, 123.4, 200.0, 333.3}
+
+	for _, freq := range freqSet {
+		x := createSin(freq, sampleRate, sampleRate/2)
+		testWORLDEstimatorBase(t, "DIO", DIO, x, freq, sampleRate, frameShift, 5.0)
+		testWORLDEstimatorBase(t, "DIO+StoneMask", dioWithStoneMask, x, freq,
+			sampleRate, frameShift, 0.2)
+
+		x = createHarmonics(freq, sampleRate, sampleRate/2)
+		testWORLDEstimatorBase(t, "DIO+StoneMask", dioWithStoneMask, x, freq,
+			sampleRate, frameShift, 0.2)
+	}
+}
+
+func TestHarvest(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	freqSet := []float64{100.0, 123.4, 200.0, 333.3}
+
+	for _, freq := range freqSet {
+		x := createHarmonics(freq, sampleRate, sampleRate/2)
+		testWORLDEstimatorBase(t, "Harvest", Harvest, x, freq,
+			sampleRate, frameShift, 0.1)
+	}
+}
+
+func TestHarvestVoicing(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+
+	// 0.25 sec voiced, 0.25 sec noise, 0.25 sec voiced
+	x := createHarmonics(150.0, sampleRate, sampleRate*3/4)
+	r := rand.New(rand.NewSource(0))
+	for i := sampleRate / 4; i < sampleRate/2; i++ {
+		x[i] = r.NormFloat64() * 0.1
+	}
+
+	f0Sequence := Harvest(x, sampleRate, frameShift, 60.0, 700.0)
+
+	framesPerQuarter := sampleRate / 4 / frameShift
+	for i := framesPerQuarter + 10; i < 2*framesPerQuarter-10; i++ {
+		if f0Sequence[i] != 0.0 {
+			t.Errorf("Frame %d should be unvoiced, but f0 is %f.", i, f0Sequence[i])
+		}
+	}
+	for i := 2*framesPerQuarter + 10; i < len(f0Sequence)-10; i++ {
+		if math.Abs(f0Sequence[i]-150.0) > 0.1 {
+			t.Errorf("The estimate is %f at frame %d, want 150 Hz.", f0Sequence[i], i)
+		}
+	}
+}
+
+func TestWorldEstimatorsEmptyInput(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	estimators := map[string]pitch.Estimator{
+		"DIO":     NewDIOEstimator(sampleRate, frameShift, 60.0, 700.0),
+		"Harvest": NewHarvestEstimator(sampleRate, frameShift, 60.0, 700.0),
+	}
+	if candidate, _ := zeroCrossingF0(nil, sampleRate, []float64{0.0}); candidate != nil {
+		t.Errorf("Candidate of empty signal is %v, want nil.", candidate)
+	}
+	for name, e := range estimators {
+		c := e.Estimate([]float64{})
+		if c.Len() != 1 || c.F0[0] != 0.0 || c.Voiced[0] {
+			t.Errorf("%s: contour of empty input is %v, want one unvoiced frame.",
+				name, c.F0)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package f0
+
+import (
+	"github.com/r9y9/gossp/pitch"
+	"math"
+)
+
+// SWIPEEstimator represents a pitch.Estimator based on SWIPE.
+type SWIPEEstimator struct {
+	SampleRate int
+	FrameShift int
+	Min        float64 // Lower bound of f0 search range in Hz
+	Max        float64 // Upper bound of f0 search range in Hz
+}
+
+// NewSWIPEEstimator returns a new SWIPEEstimator instance.
+func NewSWIPEEstimator(sampleRate, frameShift int,
+	min, max float64) *SWIPEEstimator {
+	return &SWIPEEstimator{sampleRate, frameShift, min, max}
+}
+
+// Estimate returns an f0 contour for a given audio buffer.
+func (e *SWIPEEstimator) Estimate(audioBuffer []float64) *pitch.Contour {
+	f0Sequence := SWIPE(audioBuffer, e.SampleRate, e.FrameShift, e.Min, e.Max)
+	return pitch.NewContour(f0Sequence, e.SampleRate, e.FrameShift)
+}
+
+// DIOEstimator represents a pitch.Estimator based on DIO. If Refine is set
+// to true, the estimate is refined by StoneMask.
+type DIOEstimator struct {
+	SampleRate int
+	FrameShift int
+	Min        float64 // Lower bound of f0 search range in Hz
+	Max        float64 // Upper bound of f0 search range in Hz
+	Refine     bool
+}
+
+// NewDIOEstimator returns a new DIOEstimator instance that refines the
+// estimate by StoneMask.
+func NewDIOEstimator(sampleRate, frameShift int,
+	min, max float64) *DIOEstimator {
+	return &DIOEstimator{sampleRate, frameShift, min, max, true}
+}
+
+// Estimate returns an f0 contour for a given audio buffer.
+func (e *DIOEstimator) Estimate(audioBuffer []float64) *pitch.Contour {
+	f0Sequence := DIO(audioBuffer, e.SampleRate, e.FrameShift, e.Min, e.Max)
+	if e.Refine {
+		f0Sequence = StoneMask(audioBuffer, e.SampleRate, e.FrameShift,
+			f0Sequence)
+	}
+	return pitch.NewContour(f0Sequence, e.SampleRate, e.FrameShift)
+}
+
+// HarvestEstimator represents a pitch.Estimator based on Harvest.
+type HarvestEstimator struct {
+	SampleRate int
+	FrameShift int
+	Min        float64 // Lower bound of f0 search range in Hz
+	Max        float64 // Upper bound of f0 search range in Hz
+}
+
+// NewHarvestEstimator returns a new HarvestEstimator instance.
+func NewHarvestEstimator(sampleRate, frameShift int,
+	min, max float64) *HarvestEstimator {
+	return &HarvestEstimator{sampleRate, frameShift, min, max}
+}
+
+// Estimate returns an f0 contour for a given audio buffer.
+func (e *HarvestEstimator) Estimate(audioBuffer []float64) *pitch.Contour {
+	f0Sequence := Harvest(audioBuffer, e.SampleRate, e.FrameShift, e.Min, e.Max)
+	return pitch.NewContour(f0Sequence, e.SampleRate, e.FrameShift)
+}
+
+// Estimate returns an f0 contour for a given audio buffer. The confidence
+// of each frame is the voiced probability.
+func (p *PYIN) Estimate(audioBuffer []float64) *pitch.Contour {
+	f0Sequence, voicedProb := p.ComputeF0Sequence(audioBuffer)
+	c := pitch.NewContour(f0Sequence, p.SampleRate, p.FrameShift)
+	copy(c.Confidence, voicedProb)
+	return c
+}
+
+// Estimate returns an f0 contour for a given audio buffer. The confidence
+// of each frame is the peak normalized cross-correlation.
+func (r *RAPT) Estimate(audioBuffer []float64) *pitch.Contour {
+	f0Sequence, _, _, acPeak := r.ComputeF0Sequence(audioBuffer)
+	c := pitch.NewContour(f0Sequence, r.SampleRate, r.FrameShift())
+	for i, val := range acPeak {
+		c.Confidence[i] = math.Max(0.0, math.Min(val, 1.0))
+	}
+	return c
+}
+
+// Estimate returns an f0 contour for a given audio buffer. The n-th frame
+// of length BufferSize is centered at n*FrameShift, and the confidence of
+// each frame is the periodicity given by ComputeF0.
+func (y *YIN) Estimate(audioBuffer []float64) *pitch.Contour {
+	numFrames := len(audioBuffer)/y.FrameShift + 1
+	f0Sequence := make([]float64, numFrames)
+	confidence := make([]float64, numFrames)
+
+	frame := make([]float64, y.BufferSize)
+	for i := range f0Sequence {
+		start := i*y.FrameShift - len(frame)/2
+		for j := range frame {
+			if start+j >= 0 && start+j < len(audioBuffer) {
+				frame[j] = audioBuffer[start+j]
+			} else {
+				frame[j] = 0.0
+			}
+		}
+		f0Sequence[i], confidence[i] = y.ComputeF0(frame)
+	}
+
+	c := pitch.NewContour(f0Sequence, y.SampleRate, y.FrameShift)
+	copy(c.Confidence, confidence)
+	return c
+}
@@ -0,0 +1,180 @@
+package f0
+
+// Reference:
+// W. Chu and A. Alwan, "Reducing F0 frame error of F0 tracking algorithms
+// under noisy conditions with an unvoiced/voiced classification frontend,"
+// Proc. ICASSP, pp.3969-3972, 2009.
+
+import (
+	"github.com/r9y9/gossp/pitch"
+	"math"
+)
+
+// GrossErrorThreshold is the relative deviation from reference f0 above
+// which an estimate is regarded as a gross pitch error.
+const GrossErrorThreshold = 0.2
+
+// Metrics represents pitch tracking evaluation metrics.
+type Metrics struct {
+	GPE       float64 // Gross Pitch Error
+	VDE       float64 // Voicing Decision Error
+	FFE       float64 // F0 Frame Error
+	FPE       float64 // Fine Pitch Error in percent
+	RMSECents float64 // Root mean squared error in cents
+}
+
+// Evaluate computes all metrics of an estimated contour against a
+// reference contour. The estimated contour is resampled at the times of the
+// reference contour.
+func Evaluate(reference, estimated *pitch.Contour) *Metrics {
+	return &Metrics{
+		GPE:       GrossPitchError(reference, estimated),
+		VDE:       VoicingDecisionError(reference, estimated),
+		FFE:       F0FrameError(reference, estimated),
+		FPE:       FinePitchError(reference, estimated),
+		RMSECents: RMSEInCents(reference, estimated),
+	}
+}
+
+// GrossPitchError returns the proportion of frames, among frames voiced in
+// both contours, whose relative f0 deviation exceeds GrossErrorThreshold.
+func GrossPitchError(reference, estimated *pitch.Contour) float64 {
+	f0Sequence, voiced := Resample(estimated, reference.Times)
+	numVoiced, numErrors := 0, 0
+	for i, r := range reference.F0 {
+		if !reference.Voiced[i] || !voiced[i] {
+			continue
+		}
+		numVoiced++
+		if isGrossError(r, f0Sequence[i]) {
+			numErrors++
+		}
+	}
+	return ratio(numErrors, numVoiced)
+}
+
+// VoicingDecisionError returns the proportion of frames whose voicing
+// decisions differ.
+func VoicingDecisionError(reference, estimated *pitch.Contour) float64 {
+	_, voiced := Resample(estimated, reference.Times)
+	numErrors := 0
+	for i := range reference.Voiced {
+		if reference.Voiced[i] != voiced[i] {
+			numErrors++
+		}
+	}
+	return ratio(numErrors, reference.Len())
+}
+
+// F0FrameError returns the proportion of frames that have either a voicing
+// decision error or a gross pitch error.
+func F0FrameError(reference, estimated *pitch.Contour) float64 {
+	f0Sequence, voiced := Resample(estimated, reference.Times)
+	numErrors := 0
+	for i, r := range reference.F0 {
+		switch {
+		case reference.Voiced[i] != voiced[i]:
+			numErrors++
+		case voiced[i] && isGrossError(r, f0Sequence[i]):
+			numErrors++
+		}
+	}
+	return ratio(numErrors, reference.Len())
+}
+
+// FinePitchError returns the standard deviation of the relative f0
+// deviation in percent over frames voiced in both contours without gross
+// pitch errors.
+func FinePitchError(reference, estimated *pitch.Contour) float64 {
+	f0Sequence, voiced := Resample(estimated, reference.Times)
+	var deviations []float64
+	for i, r := range reference.F0 {
+		if !reference.Voiced[i] || !voiced[i] || isGrossError(r, f0Sequence[i]) {
+			continue
+		}
+		deviations = append(deviations, 100.0*(f0Sequence[i]-r)/r)
+	}
+	if len(deviations) == 0 {
+		return 0.0
+	}
+
+	mean := 0.0
+	for _, d := range deviations {
+		mean += d
+	}
+	mean /= float64(len(deviations))
+	variance := 0.0
+	for _, d := range deviations {
+		variance += (d - mean) * (d - mean)
+	}
+	return math.Sqrt(variance / float64(len(deviations)))
+}
+
+// RMSEInCents returns the root mean squared error in cents over frames
+// voiced in both contours.
+func RMSEInCents(reference, estimated *pitch.Contour) float64 {
+	f0Sequence, voiced := Resample(estimated, reference.Times)
+	sum, n := 0.0, 0
+	for i, r := range reference.F0 {
+		if !reference.Voiced[i] || !voiced[i] {
+			continue
+		}
+		cents := 1200.0 * math.Log2(f0Sequence[i]/r)
+		sum += cents * cents
+		n++
+	}
+	if n == 0 {
+		return 0.0
+	}
+	return math.Sqrt(sum / float64(n))
+}
+
+// Resample returns f0 and voicing decisions of a contour at given times.
+// The voicing decision is taken from the nearest frame, and f0 is linearly
+// interpolated between adjacent frames if both of them are voiced.
+func Resample(c *pitch.Contour, times []float64) ([]float64, []bool) {
+	f0Sequence := make([]float64, len(times))
+	voiced := make([]bool, len(times))
+	if c.Len() == 0 {
+		return f0Sequence, voiced
+	}
+
+	k := 0
+	for i, t := range times {
+		// find k such that c.Times[k] <= t < c.Times[k+1]
+		for k > 0 && c.Times[k] > t {
+			k--
+		}
+		for k < c.Len()-1 && c.Times[k+1] <= t {
+			k++
+		}
+
+		nearest := k
+		if k < c.Len()-1 && c.Times[k+1]-t < t-c.Times[k] {
+			nearest = k + 1
+		}
+		if !c.Voiced[nearest] {
+			continue
+		}
+		voiced[i] = true
+		f0Sequence[i] = c.F0[nearest]
+
+		if k < c.Len()-1 && t > c.Times[k] && c.Voiced[k] && c.Voiced[k+1] {
+			s := (t - c.Times[k]) / (c.Times[k+1] - c.Times[k])
+			f0Sequence[i] = c.F0[k] + s*(c.F0[k+1]-c.F0[k])
+		}
+	}
+
+	return f0Sequence, voiced
+}
+
+func isGrossError(reference, estimated float64) bool {
+	return math.Abs(estimated/reference-1.0) > GrossErrorThreshold
+}
+
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0.0
+	}
+	return float64(numerator) / float64(denominator)
+}
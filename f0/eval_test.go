@@ -0,0 +1,62 @@
+package f0
+
+import (
+	"github.com/r9y9/gossp/pitch"
+	"math"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	reference := pitch.NewContour([]float64{0, 100, 100, 100, 100, 100, 100, 0, 0, 0}, 16000, 80)
+	estimated := pitch.NewContour([]float64{0, 0, 101, 99, 200, 100, 100, 100, 0, 0}, 16000, 80)
+
+	m := Evaluate(reference, estimated)
+
+	// voiced in both: frames 2, 3, 4, 5, 6, gross error: frame 4
+	if math.Abs(m.GPE-1.0/5.0) > 1.0e-10 {
+		t.Errorf("GPE is %f, want %f.", m.GPE, 1.0/5.0)
+	}
+	// voicing errors: frames 1 and 7
+	if math.Abs(m.VDE-2.0/10.0) > 1.0e-10 {
+		t.Errorf("VDE is %f, want %f.", m.VDE, 2.0/10.0)
+	}
+	if math.Abs(m.FFE-3.0/10.0) > 1.0e-10 {
+		t.Errorf("FFE is %f, want %f.", m.FFE, 3.0/10.0)
+	}
+	// fine errors: +1%, -1%, 0%, 0%
+	expectedFPE := math.Sqrt(2.0 / 4.0)
+	if math.Abs(m.FPE-expectedFPE) > 1.0e-10 {
+		t.Errorf("FPE is %f, want %f.", m.FPE, expectedFPE)
+	}
+	c1, c2 := 1200.0*math.Log2(1.01), 1200.0*math.Log2(0.99)
+	expectedRMSE := math.Sqrt((c1*c1 + c2*c2 + 1200.0*1200.0) / 5.0)
+	if math.Abs(m.RMSECents-expectedRMSE) > 1.0e-10 {
+		t.Errorf("RMSE is %f cents, want %f.", m.RMSECents, expectedRMSE)
+	}
+}
+
+func TestEvaluateDifferentFrameRates(t *testing.T) {
+	sampleRate := 16000
+
+	// 5 ms and 10 ms frame shift
+	f0Fine := make([]float64, 101)
+	f0Coarse := make([]float64, 51)
+	for i := range f0Fine {
+		f0Fine[i] = 100.0 + float64(i)
+	}
+	for i := range f0Coarse {
+		f0Coarse[i] = 100.0 + 2.0*float64(i)
+	}
+	fine := pitch.NewContour(f0Fine, sampleRate, 80)
+	coarse := pitch.NewContour(f0Coarse, sampleRate, 160)
+
+	for _, pair := range [][2]*pitch.Contour{{fine, coarse}, {coarse, fine}} {
+		m := Evaluate(pair[0], pair[1])
+		if m.GPE != 0.0 || m.VDE != 0.0 || m.FFE != 0.0 {
+			t.Errorf("GPE, VDE and FFE are %f, %f, %f, want zero.", m.GPE, m.VDE, m.FFE)
+		}
+		if m.RMSECents > 1.0e-8 {
+			t.Errorf("RMSE is %f cents, want zero.", m.RMSECents)
+		}
+	}
+}
@@ -1,2 +1,4 @@
 // Package f0 provides support for fundamental frequency (f0) estimatnion.
+// Estimators in this package return pitch.Contour and implement
+// pitch.Estimator.
 package f0
@@ -0,0 +1,53 @@
+package f0
+
+import (
+	"github.com/r9y9/gossp/pitch"
+	"math"
+	"testing"
+)
+
+func TestEstimators(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	freq := 150.0
+
+	yin := NewYIN(sampleRate)
+	yin.BufferSize, yin.FrameShift = 1024, frameShift
+	pyin := NewPYIN(sampleRate, frameShift)
+	pyin.FrameLen = 1024
+	rapt := NewRAPT(sampleRate)
+	rapt.FrameStep = float64(frameShift) / float64(sampleRate)
+
+	estimators := map[string]pitch.Estimator{
+		"YIN":     yin,
+		"PYIN":    pyin,
+		"RAPT":    rapt,
+		"DIO":     NewDIOEstimator(sampleRate, frameShift, 60.0, 700.0),
+		"Harvest": NewHarvestEstimator(sampleRate, frameShift, 60.0, 700.0),
+	}
+
+	x := createHarmonics(freq, sampleRate, sampleRate/2)
+	expectedLen := len(x)/frameShift + 1
+
+	for name, estimator := range estimators {
+		c := estimator.Estimate(x)
+		if c.Len() != expectedLen || len(c.Times) != expectedLen ||
+			len(c.Confidence) != expectedLen || len(c.Voiced) != expectedLen {
+			t.Errorf("%s: The length of contour is %d, want %d.", name, c.Len(), expectedLen)
+			continue
+		}
+
+		for i := 10; i < c.Len()-10; i++ {
+			expectedTime := float64(i*frameShift) / float64(sampleRate)
+			if math.Abs(c.Times[i]-expectedTime) > 1.0e-12 {
+				t.Errorf("%s: Time of frame %d is %f, want %f.", name, i, c.Times[i], expectedTime)
+			}
+			if !c.Voiced[i] || math.Abs(c.F0[i]-freq) > 1.0 {
+				t.Errorf("%s: The estimate is %f at frame %d, want %f.", name, c.F0[i], i, freq)
+			}
+			if c.Confidence[i] < 0.5 || c.Confidence[i] > 1.0 {
+				t.Errorf("%s: Confidence is %f at frame %d, want within [0.5, 1].",
+					name, c.Confidence[i], i)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package f0
+
+// Reference:
+// M. Morise, "Harvest: A high-performance fundamental frequency estimator
+// from speech signals," Proc. INTERSPEECH, pp.2321-2325, 2017.
+
+import (
+	"github.com/r9y9/gossp/window"
+	"math"
+	"sort"
+)
+
+const (
+	HarvestChannelsInOctave = 40.0
+	HarvestAllowedRange     = 0.1
+
+	harvestScoreThreshold = 2.5
+	harvestReliableRange  = 0.05
+)
+
+// Harvest estimates an f0 sequence by Harvest used in WORLD. The n-th
+// element corresponds to the time n*frameShift and f0 is set to zero for
+// unvoiced frames. Compared to DIO, Harvest is slower but more robust
+// in voicing decision. An empty audio buffer gives a single unvoiced frame.
+func Harvest(audioBuffer []float64, sampleRate int, frameShift int,
+	min, max float64) []float64 {
+	fs := float64(sampleRate)
+	numFrames := numF0Frames(len(audioBuffer), frameShift)
+	if len(audioBuffer) == 0 {
+		// a single unvoiced frame
+		return make([]float64, numFrames)
+	}
+	positions := make([]float64, numFrames)
+	for i := range positions {
+		positions[i] = float64(i * frameShift)
+	}
+
+	maxHalfAverageLength := int(fs/min/2.0 + 0.5)
+	fftSize := nextPow2(len(audioBuffer) + 4*maxHalfAverageLength +
+		2*int(fs/lowCutFrequency+0.5) + 2)
+	spectrum := spectrumForEstimation(audioBuffer, sampleRate, fftSize)
+
+	// Raw candidates from zero-crossings of band-pass filtered signals
+	rawCandidates := make([][]float64, numFrames)
+	numBands := 1 + int(math.Log2(max/min)*HarvestChannelsInOctave)
+	for i := 0; i < numBands; i++ {
+		boundaryF0 := min * math.Pow(2.0, float64(i)/HarvestChannelsInOctave)
+		halfAverageLength := int(fs/boundaryF0/2.0 + 0.5)
+
+		filter := window.CreateNuttall(4*halfAverageLength + 1)
+		for k := range filter {
+			t := float64(k-2*halfAverageLength) / fs
+			filter[k] *= math.Cos(2.0 * math.Pi * boundaryF0 * t)
+		}
+		filtered := filteredSignal(spectrum, filter, len(audioBuffer))
+
+		candidate, _ := zeroCrossingF0(filtered, sampleRate, positions)
+		if candidate == nil {
+			continue
+		}
+		for j, f := range candidate {
+			if f > boundaryF0*1.1 || f < boundaryF0*0.9 || f > max || f < min {
+				continue
+			}
+			rawCandidates[j] = append(rawCandidates[j], f)
+		}
+	}
+
+	// Refinement by instantaneous frequency
+	candidates := make([][]float64, numFrames)
+	scores := make([][]float64, numFrames)
+	for j, raw := range rawCandidates {
+		sort.Float64s(raw)
+		last := 0.0
+		for _, f := range raw {
+			// neighboring channels give almost the same candidate
+			if last > 0.0 && f/last < 1.02 {
+				continue
+			}
+			last = f
+			refined, score := refineF0(audioBuffer, sampleRate, positions[j], f)
+			if refined < min || refined > max || score < harvestScoreThreshold {
+				continue
+			}
+			candidates[j] = append(candidates[j], refined)
+			scores[j] = append(scores[j], score)
+		}
+	}
+
+	// Search the most reliable candidate of each frame
+	base := make([]float64, numFrames)
+	for j := range candidates {
+		bestScore := 0.0
+		for k, f := range candidates[j] {
+			if !isReliableCandidate(f, candidates, j) {
+				continue
+			}
+			if scores[j][k] > bestScore {
+				base[j], bestScore = f, scores[j][k]
+			}
+		}
+	}
+
+	voiceRangeMinimum := int(0.5+fs/float64(frameShift)/min)*2 + 1
+	return fixF0Contour(base, candidates, voiceRangeMinimum,
+		HarvestAllowedRange)
+}
+
+// isReliableCandidate reports whether an f0 candidate at the given frame
+// has a close candidate in either of the adjacent frames.
+func isReliableCandidate(f0 float64, candidates [][]float64, index int) bool {
+	for _, j := range []int{index - 1, index + 1} {
+		if j < 0 || j >= len(candidates) {
+			continue
+		}
+		for _, f := range candidates[j] {
+			if math.Abs(f-f0)/f0 < harvestReliableRange {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package f0
+
+import (
+	"github.com/r9y9/gossp"
+	"math"
+	"sort"
+)
+
+// InterpolationMethod specifies how unvoiced segments are interpolated.
+type InterpolationMethod int
+
+const (
+	LinearInterpolation InterpolationMethod = iota
+	SplineInterpolation
+)
+
+// InterpolateUnvoiced returns an f0 sequence whose unvoiced frames (zero f0)
+// are interpolated from voiced frames. Unvoiced frames before the first and
+// after the last voiced frame are filled with the nearest voiced f0. If
+// there are no voiced frames, it returns a copy of the input.
+func InterpolateUnvoiced(f0Sequence []float64,
+	method InterpolationMethod) []float64 {
+	voiced := make([]bool, len(f0Sequence))
+	for i, val := range f0Sequence {
+		voiced[i] = val > 0.0
+	}
+	return interpolateGaps(f0Sequence, voiced, method)
+}
+
+// ContinuousLogF0 returns a continuous log-f0 sequence, in which unvoiced
+// frames are interpolated in the log domain, and voiced flags of each frame.
+func ContinuousLogF0(f0Sequence []float64,
+	method InterpolationMethod) ([]float64, []bool) {
+	logF0 := make([]float64, len(f0Sequence))
+	voiced := make([]bool, len(f0Sequence))
+	for i, val := range f0Sequence {
+		if val > 0.0 {
+			logF0[i] = math.Log(val)
+			voiced[i] = true
+		}
+	}
+	return interpolateGaps(logF0, voiced, method), voiced
+}
+
+// LogF0ToF0 converts a log-f0 sequence to f0 with unvoiced frames set to
+// zero. It is the inverse of ContinuousLogF0.
+func LogF0ToF0(logF0 []float64, voiced []bool) []float64 {
+	f0Sequence := make([]float64, len(logF0))
+	for i, val := range logF0 {
+		if voiced[i] {
+			f0Sequence[i] = math.Exp(val)
+		}
+	}
+	return f0Sequence
+}
+
+// interpolateGaps interpolates values of frames where voiced is false.
+func interpolateGaps(values []float64, voiced []bool,
+	method InterpolationMethod) []float64 {
+	interpolated := make([]float64, len(values))
+	copy(interpolated, values)
+
+	var x, y, xi []float64
+	for i, val := range values {
+		if voiced[i] {
+			x = append(x, float64(i))
+			y = append(y, val)
+		} else {
+			xi = append(xi, float64(i))
+		}
+	}
+	if len(x) == 0 || len(xi) == 0 {
+		return interpolated
+	}
+
+	var yi []float64
+	switch method {
+	case SplineInterpolation:
+		yi = gossp.Spline(x, y, xi)
+	default:
+		yi = linearInterpolation(x, y, xi)
+	}
+
+	for i, index := range xi {
+		switch {
+		case index < x[0]:
+			interpolated[int(index)] = y[0]
+		case index > x[len(x)-1]:
+			interpolated[int(index)] = y[len(y)-1]
+		default:
+			interpolated[int(index)] = yi[i]
+		}
+	}
+
+	return interpolated
+}
+
+// MedianSmoothing returns an f0 sequence smoothed by a median filter of a
+// given width. Only voiced frames are smoothed and only voiced neighbors are
+// used, so the voicing decision is preserved.
+func MedianSmoothing(f0Sequence []float64, width int) []float64 {
+	smoothed := make([]float64, len(f0Sequence))
+	half := width / 2
+	buf := make([]float64, 0, width)
+
+	for i, val := range f0Sequence {
+		if val <= 0.0 {
+			continue
+		}
+		buf = voicedNeighbors(f0Sequence, i, half, buf[:0])
+		smoothed[i] = median(buf)
+	}
+
+	return smoothed
+}
+
+// MovingAverageSmoothing returns an f0 sequence smoothed by a moving average
+// of a given width. Only voiced frames are smoothed and only voiced
+// neighbors are used, so the voicing decision is preserved.
+func MovingAverageSmoothing(f0Sequence []float64, width int) []float64 {
+	smoothed := make([]float64, len(f0Sequence))
+	half := width / 2
+	buf := make([]float64, 0, width)
+
+	for i, val := range f0Sequence {
+		if val <= 0.0 {
+			continue
+		}
+		buf = voicedNeighbors(f0Sequence, i, half, buf[:0])
+		sum := 0.0
+		for _, v := range buf {
+			sum += v
+		}
+		smoothed[i] = sum / float64(len(buf))
+	}
+
+	return smoothed
+}
+
+// DetectOctaveJumps returns indices of voiced frames whose f0 is about
+// double or half of the median f0 of voiced neighbors within a given width.
+func DetectOctaveJumps(f0Sequence []float64, width int) []int {
+	var indices []int
+	for i, factor := range octaveFactors(f0Sequence, width) {
+		if factor != 1.0 {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// CorrectOctaveJumps returns an f0 sequence whose octave jumps detected by
+// DetectOctaveJumps are corrected by doubling or halving f0.
+func CorrectOctaveJumps(f0Sequence []float64, width int) []float64 {
+	corrected := make([]float64, len(f0Sequence))
+	for i, factor := range octaveFactors(f0Sequence, width) {
+		corrected[i] = f0Sequence[i] * factor
+	}
+	return corrected
+}
+
+// octaveFactors returns factors to correct octave jumps of each frame.
+func octaveFactors(f0Sequence []float64, width int) []float64 {
+	// Tolerance in octaves to regard f0 ratio as an octave jump
+	const tolerance = 0.25
+
+	factors := make([]float64, len(f0Sequence))
+	half := width / 2
+	buf := make([]float64, 0, width)
+
+	for i, val := range f0Sequence {
+		factors[i] = 1.0
+		if val <= 0.0 {
+			continue
+		}
+		buf = voicedNeighbors(f0Sequence, i, half, buf[:0])
+		octaves := math.Log2(val / median(buf))
+		switch {
+		case math.Abs(octaves-1.0) < tolerance:
+			factors[i] = 0.5
+		case math.Abs(octaves+1.0) < tolerance:
+			factors[i] = 2.0
+		}
+	}
+
+	return factors
+}
+
+// RemoveShortVoicedSegments returns an f0 sequence in which voiced segments
+// shorter than minLength frames are regarded as unvoiced.
+func RemoveShortVoicedSegments(f0Sequence []float64, minLength int) []float64 {
+	result := make([]float64, len(f0Sequence))
+	copy(result, f0Sequence)
+
+	start := -1
+	for i := 0; i <= len(f0Sequence); i++ {
+		voiced := i < len(f0Sequence) && f0Sequence[i] > 0.0
+		switch {
+		case voiced && start < 0:
+			start = i
+		case !voiced && start >= 0:
+			if i-start < minLength {
+				for j := start; j < i; j++ {
+					result[j] = 0.0
+				}
+			}
+			start = -1
+		}
+	}
+
+	return result
+}
+
+// voicedNeighbors appends voiced f0 within [index-half, index+half] to buf.
+func voicedNeighbors(f0Sequence []float64, index, half int,
+	buf []float64) []float64 {
+	for j := index - half; j <= index+half; j++ {
+		if j >= 0 && j < len(f0Sequence) && f0Sequence[j] > 0.0 {
+			buf = append(buf, f0Sequence[j])
+		}
+	}
+	return buf
+}
+
+func median(x []float64) float64 {
+	sorted := make([]float64, len(x))
+	copy(sorted, x)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2.0
+}
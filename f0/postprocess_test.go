@@ -0,0 +1,117 @@
+package f0
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInterpolateUnvoiced(t *testing.T) {
+	f0Sequence := []float64{0, 0, 100, 0, 0, 130, 140, 0, 160, 0}
+
+	expected := []float64{100, 100, 100, 110, 120, 130, 140, 150, 160, 160}
+	linear := InterpolateUnvoiced(f0Sequence, LinearInterpolation)
+	for i := range expected {
+		if math.Abs(linear[i]-expected[i]) > 1.0e-10 {
+			t.Errorf("Linear: Index %d, %f, want %f.", i, linear[i], expected[i])
+		}
+	}
+
+	spline := InterpolateUnvoiced(f0Sequence, SplineInterpolation)
+	for i, val := range f0Sequence {
+		if val > 0.0 && spline[i] != val {
+			t.Errorf("Spline: Voiced frame %d is modified: %f, want %f.", i, spline[i], val)
+		}
+		if spline[i] < 100.0 || spline[i] > 160.0 {
+			t.Errorf("Spline: Index %d, %f, want within [100, 160].", i, spline[i])
+		}
+	}
+}
+
+func TestSplineInterpolationOfLine(t *testing.T) {
+	// Spline interpolation of points on a line must be the line
+	f0Sequence := make([]float64, 50)
+	for i := range f0Sequence {
+		if i%7 != 3 && i%5 != 1 {
+			f0Sequence[i] = 100.0 + 2.0*float64(i)
+		}
+	}
+	f0Sequence[0], f0Sequence[len(f0Sequence)-1] = 100.0, 100.0+2.0*49.0
+
+	spline := InterpolateUnvoiced(f0Sequence, SplineInterpolation)
+	for i, val := range spline {
+		if math.Abs(val-(100.0+2.0*float64(i))) > 1.0e-8 {
+			t.Errorf("Index %d, %f, want %f.", i, val, 100.0+2.0*float64(i))
+		}
+	}
+}
+
+func TestContinuousLogF0(t *testing.T) {
+	f0Sequence := []float64{0, 100, 0, 400, 0}
+	logF0, voiced := ContinuousLogF0(f0Sequence, LinearInterpolation)
+
+	if math.Abs(logF0[2]-math.Log(200.0)) > 1.0e-10 {
+		t.Errorf("Interpolated log-f0 is %f, want %f.", logF0[2], math.Log(200.0))
+	}
+
+	restored := LogF0ToF0(logF0, voiced)
+	for i := range f0Sequence {
+		if math.Abs(restored[i]-f0Sequence[i]) > 1.0e-10 {
+			t.Errorf("Index %d, %f, want %f.", i, restored[i], f0Sequence[i])
+		}
+	}
+}
+
+func TestSmoothing(t *testing.T) {
+	f0Sequence := []float64{0, 100, 100, 150, 100, 100, 0, 0, 120, 120}
+
+	median := MedianSmoothing(f0Sequence, 3)
+	expected := []float64{0, 100, 100, 100, 100, 100, 0, 0, 120, 120}
+	for i := range expected {
+		if median[i] != expected[i] {
+			t.Errorf("Median: Index %d, %f, want %f.", i, median[i], expected[i])
+		}
+	}
+
+	average := MovingAverageSmoothing(f0Sequence, 3)
+	expected = []float64{0, 100, 350.0 / 3.0, 350.0 / 3.0, 350.0 / 3.0, 100, 0, 0, 120, 120}
+	for i := range expected {
+		if math.Abs(average[i]-expected[i]) > 1.0e-10 {
+			t.Errorf("Moving average: Index %d, %f, want %f.", i, average[i], expected[i])
+		}
+	}
+}
+
+func TestOctaveJumps(t *testing.T) {
+	f0Sequence := []float64{0, 100, 101, 202, 102, 51, 103, 104, 0}
+
+	indices := DetectOctaveJumps(f0Sequence, 5)
+	expectedIndices := []int{3, 5}
+	if len(indices) != len(expectedIndices) {
+		t.Fatalf("Detected octave jumps %v, want %v.", indices, expectedIndices)
+	}
+	for i := range indices {
+		if indices[i] != expectedIndices[i] {
+			t.Errorf("Detected octave jumps %v, want %v.", indices, expectedIndices)
+		}
+	}
+
+	corrected := CorrectOctaveJumps(f0Sequence, 5)
+	expected := []float64{0, 100, 101, 101, 102, 102, 103, 104, 0}
+	for i := range expected {
+		if corrected[i] != expected[i] {
+			t.Errorf("Index %d, %f, want %f.", i, corrected[i], expected[i])
+		}
+	}
+}
+
+func TestRemoveShortVoicedSegments(t *testing.T) {
+	f0Sequence := []float64{100, 0, 100, 100, 0, 100, 100, 100, 0, 100}
+
+	result := RemoveShortVoicedSegments(f0Sequence, 3)
+	expected := []float64{0, 0, 0, 0, 0, 100, 100, 100, 0, 0}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Index %d, %f, want %f.", i, result[i], expected[i])
+		}
+	}
+}
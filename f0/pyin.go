@@ -0,0 +1,331 @@
+package f0
+
+// Reference:
+// M. Mauch and S. Dixon, "pYIN: A fundamental frequency estimator using
+// probabilistic threshold distributions," Proc. ICASSP, pp.659-663, 2014.
+
+import (
+	"math"
+)
+
+// Default parameters of PYIN, which follow the reference implementation
+// of pYIN.
+const (
+	DefaultPYINMin             = 60.0  // Lower bound of f0 search range in Hz
+	DefaultPYINMax             = 700.0 // Upper bound of f0 search range in Hz
+	DefaultBetaMean            = 0.15  // Mean of the threshold prior
+	DefaultBinsPerSemitone     = 5     // Pitch resolution of the HMM
+	DefaultMaxSemitoneJump     = 5     // Maximum pitch jump per frame
+	DefaultVoicingSwitchProb   = 0.01  // Probability of voicing change
+	DefaultAbsoluteMinimumProb = 0.01  // Weight of the global minimum
+)
+
+// Number of YIN thresholds over which the threshold prior is defined
+const numThresholds = 100
+
+// PYIN represents the probabilistic YIN (pYIN) fundamental frequency
+// estimator. Each frame yields multiple pitch candidates by integrating the
+// YIN absolute threshold over a beta-distributed threshold prior, and the
+// candidates are decoded by an HMM that jointly tracks pitch and voicing.
+type PYIN struct {
+	SampleRate int
+	FrameLen   int // Length of analysis frame
+	FrameShift int
+	Min        float64 // Lower bound of f0 search range in Hz
+	Max        float64 // Upper bound of f0 search range in Hz
+
+	// Mean of the beta distribution over YIN thresholds.
+	BetaMean float64
+	// Resolution of pitch states in the HMM.
+	BinsPerSemitone int
+	// Maximum pitch jump between successive frames in semitones.
+	MaxSemitoneJump int
+	// Probability of switching between voiced and unvoiced state.
+	VoicingSwitchProb float64
+	// Probability mass given to the global minimum of the difference
+	// function when no dip falls below the threshold.
+	AbsoluteMinimumProb float64
+}
+
+// PitchCandidate represents a pitch candidate and its probability.
+type PitchCandidate struct {
+	F0          float64
+	Probability float64
+}
+
+// NewPYIN returns a new PYIN instance with default parameters.
+func NewPYIN(sampleRate, frameShift int) *PYIN {
+	return &PYIN{
+		SampleRate:          sampleRate,
+		FrameLen:            DefaultBufferSize,
+		FrameShift:          frameShift,
+		Min:                 DefaultPYINMin,
+		Max:                 DefaultPYINMax,
+		BetaMean:            DefaultBetaMean,
+		BinsPerSemitone:     DefaultBinsPerSemitone,
+		MaxSemitoneJump:     DefaultMaxSemitoneJump,
+		VoicingSwitchProb:   DefaultVoicingSwitchProb,
+		AbsoluteMinimumProb: DefaultAbsoluteMinimumProb,
+	}
+}
+
+// ComputeF0Sequence computes an f0 sequence and voiced probabilities of each
+// frame for a given audio buffer. The n-th frame is centered at
+// n*FrameShift, and f0 is set to zero for unvoiced frames.
+func (p *PYIN) ComputeF0Sequence(audioBuffer []float64) ([]float64, []float64) {
+	numFrames := len(audioBuffer)/p.FrameShift + 1
+	candidates := make([][]PitchCandidate, numFrames)
+
+	frame := make([]float64, p.FrameLen)
+	for i := range candidates {
+		center := i * p.FrameShift
+		for j := range frame {
+			index := center - p.FrameLen/2 + j
+			if index >= 0 && index < len(audioBuffer) {
+				frame[j] = audioBuffer[index]
+			} else {
+				frame[j] = 0.0
+			}
+		}
+		candidates[i] = p.Candidates(frame)
+	}
+
+	return p.Track(candidates)
+}
+
+// Candidates returns pitch candidates for a given audio frame.
+func (p *PYIN) Candidates(frame []float64) []PitchCandidate {
+	w := len(frame) / 2
+	minTau := int(float64(p.SampleRate) / p.Max)
+	maxTau := int(math.Ceil(float64(p.SampleRate) / p.Min))
+	if minTau < 2 {
+		minTau = 2
+	}
+	if maxTau > w-1 {
+		maxTau = w - 1
+	}
+	if minTau >= maxTau {
+		return nil
+	}
+
+	// Step 2 and 3 of YIN
+	y := &YIN{
+		Buffer:     make([]float64, len(frame)),
+		BufferSize: len(frame),
+		SampleRate: p.SampleRate,
+	}
+	y.Difference(frame)
+	y.CumulativeMeanNormalizedDifference()
+	d := y.Buffer
+
+	prior := betaThresholdPrior(p.BetaMean)
+
+	prob := make([]float64, w)
+	for i, s := range thresholds() {
+		tau := firstDipBelow(d, s, minTau, maxTau)
+		if tau > 0 {
+			prob[tau] += prior[i]
+			continue
+		}
+		// No dip below the threshold: fall back to the global minimum
+		tau = minTau
+		for t := minTau + 1; t <= maxTau; t++ {
+			if d[t] < d[tau] {
+				tau = t
+			}
+		}
+		prob[tau] += prior[i] * p.AbsoluteMinimumProb
+	}
+
+	var candidates []PitchCandidate
+	for tau := minTau; tau <= maxTau; tau++ {
+		if prob[tau] == 0.0 {
+			continue
+		}
+		betterTau := y.ParabolicInterpolation(tau)
+		candidates = append(candidates, PitchCandidate{
+			F0:          float64(p.SampleRate) / betterTau,
+			Probability: prob[tau],
+		})
+	}
+
+	return candidates
+}
+
+// Track performs HMM-based Viterbi decoding of pitch candidates and returns
+// an f0 sequence and voiced probabilities of each frame.
+func (p *PYIN) Track(candidates [][]PitchCandidate) ([]float64, []float64) {
+	numFrames := len(candidates)
+	f0Sequence := make([]float64, numFrames)
+	voicedProb := make([]float64, numFrames)
+	if numFrames == 0 {
+		return f0Sequence, voicedProb
+	}
+
+	binsPerOctave := 12.0 * float64(p.BinsPerSemitone)
+	numBins := int(math.Log2(p.Max/p.Min)*binsPerOctave) + 1
+	binOf := func(f float64) int {
+		return int(math.Floor(math.Log2(f/p.Min)*binsPerOctave + 0.5))
+	}
+	binFreq := func(bin int) float64 {
+		return p.Min * math.Pow(2.0, float64(bin)/binsPerOctave)
+	}
+
+	// Pitch transition (triangular window) in log domain, indexed by the
+	// jump plus maxJump. The window sums to (maxJump+1)^2.
+	maxJump := p.MaxSemitoneJump * p.BinsPerSemitone
+	logTrans := make([]float64, 2*maxJump+1)
+	sum := float64((maxJump + 1) * (maxJump + 1))
+	for d := 0; d <= maxJump; d++ {
+		logTrans[maxJump-d] = math.Log(float64(maxJump+1-d) / sum)
+		logTrans[maxJump+d] = logTrans[maxJump-d]
+	}
+	logStay := math.Log(1.0 - p.VoicingSwitchProb)
+	logSwitch := math.Log(p.VoicingSwitchProb)
+
+	// States [0, numBins) are voiced, [numBins, 2*numBins) unvoiced.
+	numStates := 2 * numBins
+	logObs := func(frame []PitchCandidate) []float64 {
+		obs := make([]float64, numStates)
+		voiced := 0.0
+		for _, c := range frame {
+			bin := binOf(c.F0)
+			if bin < 0 || bin >= numBins {
+				continue
+			}
+			obs[bin] += c.Probability
+			voiced += c.Probability
+		}
+		if voiced > 1.0 {
+			voiced = 1.0
+		}
+		for j := 0; j < numBins; j++ {
+			obs[numBins+j] = (1.0 - voiced) / float64(numBins)
+		}
+		for j := range obs {
+			obs[j] = math.Log(obs[j] + 1.0e-300)
+		}
+		return obs
+	}
+
+	delta := logObs(candidates[0])
+	for j := range delta {
+		delta[j] -= math.Log(float64(numStates))
+	}
+	backTracePointer := make([][]int, numFrames)
+
+	for t := 1; t < numFrames; t++ {
+		obs := logObs(candidates[t])
+		next := make([]float64, numStates)
+		pointer := make([]int, numStates)
+		for j := 0; j < numStates; j++ {
+			bin, voiced := j%numBins, j < numBins
+			best, bestIndex := math.Inf(-1), j
+			for d := -maxJump; d <= maxJump; d++ {
+				k := bin + d
+				if k < 0 || k >= numBins {
+					continue
+				}
+				for _, fromVoiced := range []bool{true, false} {
+					i := k
+					if !fromVoiced {
+						i += numBins
+					}
+					cost := delta[i] + logTrans[maxJump+d]
+					if fromVoiced == voiced {
+						cost += logStay
+					} else {
+						cost += logSwitch
+					}
+					if cost > best {
+						best, bestIndex = cost, i
+					}
+				}
+			}
+			next[j] = best + obs[j]
+			pointer[j] = bestIndex
+		}
+		delta = next
+		backTracePointer[t] = pointer
+	}
+
+	// Backtrace
+	state := 0
+	for j := range delta {
+		if delta[j] > delta[state] {
+			state = j
+		}
+	}
+	for t := numFrames - 1; t >= 0; t-- {
+		for _, c := range candidates[t] {
+			voicedProb[t] += c.Probability
+		}
+		if voicedProb[t] > 1.0 {
+			voicedProb[t] = 1.0
+		}
+
+		if state < numBins {
+			// Pick the most probable candidate that falls near the bin
+			f0Sequence[t] = binFreq(state)
+			best := 0.0
+			for _, c := range candidates[t] {
+				bin := binOf(c.F0)
+				if bin >= state-1 && bin <= state+1 && c.Probability > best {
+					f0Sequence[t], best = c.F0, c.Probability
+				}
+			}
+		}
+
+		if t > 0 {
+			state = backTracePointer[t][state]
+		}
+	}
+
+	return f0Sequence, voicedProb
+}
+
+// firstDipBelow returns the first local minimum in [minTau, maxTau] whose
+// value is below the threshold s, or zero if there is no such dip.
+func firstDipBelow(d []float64, s float64, minTau, maxTau int) int {
+	for tau := minTau; tau <= maxTau; tau++ {
+		if d[tau] >= s {
+			continue
+		}
+		for tau+1 <= maxTau && d[tau+1] < d[tau] {
+			tau++
+		}
+		return tau
+	}
+	return 0
+}
+
+// thresholds returns YIN thresholds 0.01, 0.02, ..., 1.00.
+func thresholds() []float64 {
+	s := make([]float64, numThresholds)
+	for i := range s {
+		s[i] = float64(i+1) / float64(numThresholds)
+	}
+	return s
+}
+
+// betaThresholdPrior returns the probability of each threshold under the
+// beta distribution Beta(2, 2(1-mean)/mean).
+func betaThresholdPrior(mean float64) []float64 {
+	a := 2.0
+	b := a * (1.0 - mean) / mean
+
+	s := thresholds()
+	prior := make([]float64, len(s))
+	sum := 0.0
+	for i, x := range s {
+		// Evaluate at the center of each threshold interval
+		x -= 0.5 / float64(numThresholds)
+		prior[i] = math.Pow(x, a-1.0) * math.Pow(1.0-x, b-1.0)
+		sum += prior[i]
+	}
+	for i := range prior {
+		prior[i] /= sum
+	}
+
+	return prior
+}
@@ -0,0 +1,78 @@
+package f0
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPYIN(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	freqSet := []float64{100.0, 150.0, 220.0, 400.0}
+
+	for _, freq := range freqSet {
+		p := NewPYIN(sampleRate, frameShift)
+		p.FrameLen = 1024
+
+		f0Sequence, voicedProb := p.ComputeF0Sequence(createSin(freq, sampleRate, sampleRate/2))
+		// skip frames near edges
+		for i := 10; i < len(f0Sequence)-10; i++ {
+			if math.Abs(f0Sequence[i]-freq) > 1.0 {
+				t.Errorf("The estimate is %f of %f Hz signal at frame %d, want within 1.0 Hz.",
+					f0Sequence[i], freq, i)
+			}
+			if voicedProb[i] < 0.5 {
+				t.Errorf("Voiced probability is %f at frame %d, want greater than 0.5.",
+					voicedProb[i], i)
+			}
+		}
+	}
+}
+
+func TestPYINVoicing(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+
+	// 0.25 sec sine, 0.25 sec noise, 0.25 sec sine
+	x := createSin(200.0, sampleRate, sampleRate*3/4)
+	r := rand.New(rand.NewSource(0))
+	for i := sampleRate / 4; i < sampleRate/2; i++ {
+		x[i] = r.NormFloat64() * 0.1
+	}
+
+	p := NewPYIN(sampleRate, frameShift)
+	p.FrameLen = 1024
+	f0Sequence, _ := p.ComputeF0Sequence(x)
+
+	framesPerQuarter := sampleRate / 4 / frameShift
+	for i := range f0Sequence {
+		switch {
+		case i > 10 && i < framesPerQuarter-10:
+			fallthrough
+		case i > 2*framesPerQuarter+10 && i < len(f0Sequence)-10:
+			if math.Abs(f0Sequence[i]-200.0) > 1.0 {
+				t.Errorf("The estimate is %f at frame %d, want 200 Hz.", f0Sequence[i], i)
+			}
+		case i > framesPerQuarter+10 && i < 2*framesPerQuarter-10:
+			if f0Sequence[i] != 0.0 {
+				t.Errorf("Frame %d should be unvoiced, but f0 is %f.", i, f0Sequence[i])
+			}
+		}
+	}
+}
+
+func TestPYINSilence(t *testing.T) {
+	p := NewPYIN(16000, 80)
+	p.FrameLen = 1024
+
+	for _, c := range p.Candidates(make([]float64, p.FrameLen)) {
+		if math.IsNaN(c.F0) || math.IsInf(c.F0, 0) {
+			t.Errorf("Candidate f0 of silence is %f, want finite.", c.F0)
+		}
+	}
+	f0Sequence, _ := p.ComputeF0Sequence(make([]float64, 1600))
+	for i, f0 := range f0Sequence {
+		if f0 != 0.0 {
+			t.Errorf("f0 of silence is %f at frame %d, want unvoiced.", f0, i)
+		}
+	}
+}
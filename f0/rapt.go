@@ -0,0 +1,345 @@
+package f0
+
+// Reference:
+// D. Talkin, "A robust algorithm for pitch tracking (RAPT)," in Speech
+// Coding and Synthesis, W. B. Kleijn and K. K. Paliwal, Eds.
+// Elsevier, pp.495-518, 1995.
+
+import (
+	"github.com/r9y9/gossp/lpc"
+	"github.com/r9y9/gossp/window"
+	"math"
+	"sort"
+)
+
+// RAPT represents the Robust Algorithm for Pitch Tracking (get_f0 in ESPS).
+// Parameter names and default values follow F0_params of ESPS.
+//
+// Unlike the original RAPT, which first searches for candidates in the NCCF
+// of a downsampled signal and then refines them at the full rate, this
+// implementation computes the NCCF at the full rate over all lags in a
+// single pass, which is slower than the two-pass search of the original.
+type RAPT struct {
+	SampleRate int
+
+	MinF0        float64 // Lower bound of f0 search range in Hz
+	MaxF0        float64 // Upper bound of f0 search range in Hz
+	FrameStep    float64 // Analysis frame step in seconds
+	WindDur      float64 // Correlation window duration in seconds
+	CandThresh   float64 // Minimum NCCF peak value relative to the maximum
+	LagWeight    float64 // Linear lag taper factor for NCCF peaks
+	FreqWeight   float64 // Cost factor for f0 change between frames
+	TransCost    float64 // Fixed cost for voicing-state transition
+	TransAmp     float64 // Cost factor for amplitude change at transition
+	TransSpec    float64 // Cost factor for spectral change at transition
+	VoiceBias    float64 // Bias toward voiced (negative) or unvoiced
+	DoubleCost   float64 // Cost for octave jumps
+	MeanF0       float64 // Expected mean f0 in Hz
+	MeanF0Weight float64 // Cost factor for deviation from MeanF0
+	NCands       int     // Maximum number of candidates per frame including unvoiced
+}
+
+// NewRAPT returns a new RAPT instance with the ESPS default parameters.
+func NewRAPT(sampleRate int) *RAPT {
+	return &RAPT{
+		SampleRate:   sampleRate,
+		MinF0:        50.0,
+		MaxF0:        550.0,
+		FrameStep:    0.01,
+		WindDur:      0.0075,
+		CandThresh:   0.3,
+		LagWeight:    0.3,
+		FreqWeight:   0.02,
+		TransCost:    0.005,
+		TransAmp:     0.5,
+		TransSpec:    0.5,
+		VoiceBias:    0.0,
+		DoubleCost:   0.35,
+		MeanF0:       200.0,
+		MeanF0Weight: 0.0,
+		NCands:       20,
+	}
+}
+
+// raptCandidate represents a voicing hypothesis of a frame. Lag is zero
+// for the unvoiced hypothesis.
+type raptCandidate struct {
+	Lag  float64
+	Corr float64
+	Cost float64
+}
+
+// FrameShift returns the frame step in samples.
+func (r *RAPT) FrameShift() int {
+	return int(r.FrameStep*float64(r.SampleRate) + 0.5)
+}
+
+// ComputeF0Sequence computes f0, probability of voicing, RMS and peak
+// normalized cross-correlation of each frame for a given audio buffer.
+// The n-th frame is centered at n*FrameShift(), and f0 is set to zero for
+// unvoiced frames.
+func (r *RAPT) ComputeF0Sequence(audioBuffer []float64) ([]float64,
+	[]float64, []float64, []float64) {
+	frameShift := r.FrameShift()
+	numFrames := len(audioBuffer)/frameShift + 1
+
+	fs := float64(r.SampleRate)
+	windowLen := int(r.WindDur*fs + 0.5)
+	minLag := int(fs / r.MaxF0)
+	maxLag := int(math.Ceil(fs / r.MinF0))
+
+	// Parameters for RMS and spectral stationarity measure
+	stationarityLen := int(0.03*fs + 0.5)
+	lpcOrder := 2 + r.SampleRate/1000
+
+	sampleAt := func(i int) float64 {
+		if i < 0 || i >= len(audioBuffer) {
+			return 0.0
+		}
+		return audioBuffer[i]
+	}
+
+	rms := make([]float64, numFrames)
+	acPeak := make([]float64, numFrames)
+	stationarity := make([]float64, numFrames)
+	candidates := make([][]raptCandidate, numFrames)
+
+	hanning := window.CreateHanning(stationarityLen)
+	frame := make([]float64, stationarityLen)
+	var previousLPC []float64
+	for i := 0; i < numFrames; i++ {
+		center := i * frameShift
+
+		// RMS and LPC of Hanning windowed frame
+		energy := 0.0
+		for j := range frame {
+			frame[j] = sampleAt(center-stationarityLen/2+j) * hanning[j]
+			energy += frame[j] * frame[j]
+		}
+		rms[i] = math.Sqrt(energy / float64(stationarityLen))
+		autoCorr := lpc.Autocorrelation(frame, lpcOrder)
+		inverse := inverseFilter(autoCorr, lpcOrder)
+		if i > 0 {
+			d := itakuraDistance(previousLPC, inverse, autoCorr)
+			stationarity[i] = 0.2 / math.Max(d-0.8, 0.2)
+		}
+		previousLPC = inverse
+
+		// Normalized cross-correlation function
+		start := center - windowLen/2
+		nccf := make([]float64, maxLag+2)
+		mean := 0.0
+		for j := 0; j < windowLen; j++ {
+			mean += sampleAt(start + j)
+		}
+		mean /= float64(windowLen)
+		e0 := 0.0
+		for j := 0; j < windowLen; j++ {
+			s := sampleAt(start+j) - mean
+			e0 += s * s
+		}
+		ek := e0
+		for k := 0; k <= maxLag+1; k++ {
+			if k > 0 {
+				// sliding energy of the lagged window
+				s1, s2 := sampleAt(start+k-1)-mean, sampleAt(start+k+windowLen-1)-mean
+				ek += s2*s2 - s1*s1
+			}
+			if e0 <= 0.0 || ek <= 0.0 {
+				continue
+			}
+			sum := 0.0
+			for j := 0; j < windowLen; j++ {
+				sum += (sampleAt(start+j) - mean) * (sampleAt(start+j+k) - mean)
+			}
+			nccf[k] = sum / math.Sqrt(e0*ek)
+		}
+
+		candidates[i] = r.candidates(nccf, minLag, maxLag)
+		for _, c := range candidates[i] {
+			if c.Corr > acPeak[i] {
+				acPeak[i] = c.Corr
+			}
+		}
+		// Unvoiced hypothesis
+		candidates[i] = append(candidates[i], raptCandidate{
+			Lag:  0.0,
+			Cost: r.VoiceBias + acPeak[i],
+		})
+	}
+
+	path := r.dynamicProgramming(candidates, rms, stationarity)
+
+	f0Sequence := make([]float64, numFrames)
+	probVoice := make([]float64, numFrames)
+	for i, c := range path {
+		if c.Lag > 0.0 {
+			f0Sequence[i] = fs / c.Lag
+			probVoice[i] = 1.0
+		}
+	}
+
+	return f0Sequence, probVoice, rms, acPeak
+}
+
+// byCorr sorts candidates in descending order of correlation.
+type byCorr []raptCandidate
+
+func (c byCorr) Len() int           { return len(c) }
+func (c byCorr) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c byCorr) Less(i, j int) bool { return c[i].Corr > c[j].Corr }
+
+// candidates returns voiced hypotheses with their local costs given NCCF.
+func (r *RAPT) candidates(nccf []float64, minLag, maxLag int) []raptCandidate {
+	maxCorr := 0.0
+	for k := minLag; k <= maxLag; k++ {
+		if nccf[k] > maxCorr {
+			maxCorr = nccf[k]
+		}
+	}
+
+	var peaks []raptCandidate
+	for k := minLag; k <= maxLag; k++ {
+		if k < 1 || nccf[k] < r.CandThresh*maxCorr || nccf[k] <= 0.0 ||
+			nccf[k] < nccf[k-1] || nccf[k] < nccf[k+1] {
+			continue
+		}
+		// Parabolic interpolation of the peak
+		s0, s1, s2 := nccf[k-1], nccf[k], nccf[k+1]
+		lag, corr := float64(k), s1
+		if denom := s0 - 2.0*s1 + s2; denom < 0.0 {
+			delta := 0.5 * (s0 - s2) / denom
+			lag += delta
+			corr = s1 - 0.25*(s0-s2)*delta
+		}
+		peaks = append(peaks, raptCandidate{Lag: lag, Corr: corr})
+	}
+
+	sort.Sort(byCorr(peaks))
+	// One candidate is reserved for the unvoiced hypothesis
+	maxPeaks := r.NCands - 1
+	if maxPeaks < 0 {
+		maxPeaks = 0
+	}
+	if len(peaks) > maxPeaks {
+		peaks = peaks[:maxPeaks]
+	}
+
+	fs := float64(r.SampleRate)
+	for i := range peaks {
+		p := &peaks[i]
+		p.Cost = 1.0 - p.Corr*(1.0-r.LagWeight*p.Lag/float64(maxLag))
+		if r.MeanF0Weight > 0.0 {
+			p.Cost += r.MeanF0Weight * math.Abs(math.Log(fs/p.Lag/r.MeanF0))
+		}
+	}
+
+	return peaks
+}
+
+// dynamicProgramming returns the minimum cost path of hypotheses.
+func (r *RAPT) dynamicProgramming(candidates [][]raptCandidate,
+	rms, stationarity []float64) []raptCandidate {
+	numFrames := len(candidates)
+	path := make([]raptCandidate, numFrames)
+	if numFrames == 0 {
+		return path
+	}
+
+	cost := make([]float64, len(candidates[0]))
+	for j, c := range candidates[0] {
+		cost[j] = c.Cost
+	}
+	backTracePointer := make([][]int, numFrames)
+
+	for i := 1; i < numFrames; i++ {
+		rr := 1.0
+		if rms[i-1] > 0.0 && rms[i] > 0.0 {
+			rr = rms[i] / rms[i-1]
+		}
+
+		next := make([]float64, len(candidates[i]))
+		pointer := make([]int, len(candidates[i]))
+		for j, c := range candidates[i] {
+			best, bestIndex := math.Inf(1), 0
+			for k, p := range candidates[i-1] {
+				var trans float64
+				switch {
+				case p.Lag > 0.0 && c.Lag > 0.0:
+					// voiced to voiced: penalize f0 change and octave jump
+					d := math.Abs(math.Log(p.Lag / c.Lag))
+					d = math.Min(d, r.DoubleCost+math.Abs(d-math.Ln2))
+					trans = r.FreqWeight * d
+				case p.Lag > 0.0 && c.Lag == 0.0:
+					// voiced to unvoiced (offset)
+					trans = r.TransCost + r.TransSpec*stationarity[i] +
+						r.TransAmp*rr
+				case p.Lag == 0.0 && c.Lag > 0.0:
+					// unvoiced to voiced (onset)
+					trans = r.TransCost + r.TransSpec*stationarity[i] +
+						r.TransAmp/rr
+				}
+				if cost[k]+trans < best {
+					best, bestIndex = cost[k]+trans, k
+				}
+			}
+			next[j] = best + c.Cost
+			pointer[j] = bestIndex
+		}
+		cost = next
+		backTracePointer[i] = pointer
+	}
+
+	index := 0
+	for j := range cost {
+		if cost[j] < cost[index] {
+			index = j
+		}
+	}
+	for i := numFrames - 1; i >= 0; i-- {
+		path[i] = candidates[i][index]
+		if i > 0 {
+			index = backTracePointer[i][index]
+		}
+	}
+
+	return path
+}
+
+// inverseFilter returns the inverse filter coefficients (a[0] = 1) of a
+// given order from autocorrelation. The flat filter is returned for a
+// silent or ill-conditioned frame.
+func inverseFilter(r []float64, order int) []float64 {
+	result, err := lpc.LevinsonDurbin(r, order)
+	if err != nil {
+		a := make([]float64, order+1)
+		a[0] = 1.0
+		return a
+	}
+	a := result.Coef
+	a[0] = 1.0
+	return a
+}
+
+// itakuraDistance returns the Itakura ratio of the residual energy of the
+// inverse filter a1 to that of a2 for a signal with autocorrelation r.
+func itakuraDistance(a1, a2, r []float64) float64 {
+	residual := func(a []float64) float64 {
+		sum := 0.0
+		for i := range a {
+			for j := range a {
+				k := i - j
+				if k < 0 {
+					k = -k
+				}
+				sum += a[i] * a[j] * r[k]
+			}
+		}
+		return sum
+	}
+	e2 := residual(a2)
+	if e2 <= 0.0 {
+		return 1.0
+	}
+	return residual(a1) / e2
+}
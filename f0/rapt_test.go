@@ -0,0 +1,83 @@
+package f0
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRAPT(t *testing.T) {
+	sampleRate := 16000
+	freqSet := []float64{100.0, 123.4, 200.0, 333.3}
+
+	for _, freq := range freqSet {
+		r := NewRAPT(sampleRate)
+		x := createHarmonics(freq, sampleRate, sampleRate/2)
+		f0Sequence, probVoice, rms, acPeak := r.ComputeF0Sequence(x)
+
+		expectedLen := len(x)/r.FrameShift() + 1
+		for _, seq := range [][]float64{f0Sequence, probVoice, rms, acPeak} {
+			if len(seq) != expectedLen {
+				t.Errorf("The number of frames is %d, want %d.", len(seq), expectedLen)
+			}
+		}
+
+		// skip frames near edges
+		for i := 3; i < len(f0Sequence)-3; i++ {
+			if math.Abs(f0Sequence[i]-freq) > 1.0 {
+				t.Errorf("The estimate is %f of %f Hz signal at frame %d, want within 1.0 Hz.",
+					f0Sequence[i], freq, i)
+			}
+			if probVoice[i] != 1.0 {
+				t.Errorf("Frame %d should be voiced.", i)
+			}
+			if acPeak[i] < 0.9 {
+				t.Errorf("Peak correlation is %f at frame %d, want greater than 0.9.",
+					acPeak[i], i)
+			}
+		}
+	}
+}
+
+func TestRAPTVoicing(t *testing.T) {
+	sampleRate := 16000
+
+	// 0.25 sec voiced, 0.25 sec noise, 0.25 sec voiced
+	x := createHarmonics(150.0, sampleRate, sampleRate*3/4)
+	rnd := rand.New(rand.NewSource(0))
+	for i := sampleRate / 4; i < sampleRate/2; i++ {
+		x[i] = rnd.NormFloat64() * 0.1
+	}
+
+	r := NewRAPT(sampleRate)
+	f0Sequence, probVoice, _, _ := r.ComputeF0Sequence(x)
+
+	framesPerQuarter := sampleRate / 4 / r.FrameShift()
+	for i := framesPerQuarter + 3; i < 2*framesPerQuarter-3; i++ {
+		if f0Sequence[i] != 0.0 || probVoice[i] != 0.0 {
+			t.Errorf("Frame %d should be unvoiced, but f0 is %f.", i, f0Sequence[i])
+		}
+	}
+	for i := 2*framesPerQuarter + 3; i < len(f0Sequence)-3; i++ {
+		if math.Abs(f0Sequence[i]-150.0) > 1.0 {
+			t.Errorf("The estimate is %f at frame %d, want 150 Hz.", f0Sequence[i], i)
+		}
+	}
+}
+
+func TestRAPTNumCandidates(t *testing.T) {
+	sampleRate := 16000
+	x := createHarmonics(150.0, sampleRate, sampleRate/4)
+
+	for _, nCands := range []int{0, 1} {
+		r := NewRAPT(sampleRate)
+		r.NCands = nCands
+		f0Sequence, _, _, _ := r.ComputeF0Sequence(x)
+		for i, f0 := range f0Sequence {
+			if f0 != 0.0 {
+				t.Errorf("f0 is %f at frame %d with NCands = %d, want unvoiced.",
+					f0, i, nCands)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package f0
+
+import (
+	"math"
+)
+
+// StoneMask refines an f0 sequence estimated by e.g. DIO based on the
+// instantaneous frequencies of harmonic components. f0Sequence must be
+// sampled at every frameShift samples, as returned by DIO.
+func StoneMask(audioBuffer []float64, sampleRate, frameShift int,
+	f0Sequence []float64) []float64 {
+	refined := make([]float64, len(f0Sequence))
+
+	for i, f0 := range f0Sequence {
+		if f0 == 0.0 {
+			continue
+		}
+		position := float64(i * frameShift)
+
+		// Refinement is applied twice as in WORLD
+		r, _ := refineF0(audioBuffer, sampleRate, position, f0)
+		if r > 0.0 {
+			r, _ = refineF0(audioBuffer, sampleRate, position, r)
+		}
+
+		// Reject refinement that deviates too much from the initial f0
+		if r <= 0.0 || math.Abs(r-f0)/f0 > 0.2 {
+			r = f0
+		}
+		refined[i] = r
+	}
+
+	return refined
+}
@@ -0,0 +1,352 @@
+package f0
+
+// Common routines of the WORLD-style f0 estimators (DIO, Harvest and
+// StoneMask). See https://github.com/mmorise/World for the original
+// implementation.
+
+import (
+	"github.com/mjibson/go-dsp/fft"
+	"github.com/r9y9/gossp/window"
+	"math"
+	"math/cmplx"
+)
+
+const (
+	safeGuardMinimum = 1.0e-12
+	maximumValue     = 1.0e+12
+	lowCutFrequency  = 50.0
+)
+
+// numF0Frames returns the number of frames that the WORLD-style estimators
+// return. The n-th frame is centered at n*frameShift.
+func numF0Frames(length, frameShift int) int {
+	return length/frameShift + 1
+}
+
+func nextPow2(n int) int {
+	size := 1
+	for size < n {
+		size *= 2
+	}
+	return size
+}
+
+// spectrumForEstimation returns the spectrum of a DC-removed and low-cut
+// filtered signal, which is used in filter bank analysis.
+func spectrumForEstimation(audioBuffer []float64, sampleRate, fftSize int) []complex128 {
+	mean := 0.0
+	for _, val := range audioBuffer {
+		mean += val
+	}
+	mean /= float64(len(audioBuffer))
+
+	y := make([]float64, fftSize)
+	for i, val := range audioBuffer {
+		y[i] = val - mean
+	}
+	spectrum := fft.FFTReal(y)
+
+	// Low-cut filtering: subtract the low-pass filtered component
+	cutoffLength := int(float64(sampleRate)/lowCutFrequency+0.5)*2 + 1
+	lowPass := window.CreateHanning(cutoffLength + 2)[1 : cutoffLength+1]
+	sum := 0.0
+	for _, val := range lowPass {
+		sum += val
+	}
+	filter := make([]float64, fftSize)
+	for i, val := range lowPass {
+		// centering the filter on time zero makes it zero-phase
+		filter[(i-cutoffLength/2+fftSize)%fftSize] = val / sum
+	}
+	lowPassSpectrum := fft.FFTReal(filter)
+	for i := range spectrum {
+		spectrum[i] *= 1.0 - lowPassSpectrum[i]
+	}
+
+	return spectrum
+}
+
+// filteredSignal returns the signal filtered by a zero-phase FIR filter,
+// whose center is assumed to be at len(filter)/2.
+func filteredSignal(spectrum []complex128, filter []float64, length int) []float64 {
+	fftSize := len(spectrum)
+	x := make([]float64, fftSize)
+	for i, val := range filter {
+		x[(i-len(filter)/2+fftSize)%fftSize] = val
+	}
+	filterSpectrum := fft.FFTReal(x)
+
+	product := make([]complex128, fftSize)
+	for i := range product {
+		product[i] = spectrum[i] * filterSpectrum[i]
+	}
+	filtered := fft.IFFT(product)
+
+	y := make([]float64, length)
+	for i := range y {
+		y[i] = real(filtered[i])
+	}
+	return y
+}
+
+// zeroCrossingF0 returns f0 estimated by the mean of intervals of four
+// kinds of events (negative-going and positive-going zero-crossings, peaks
+// and dips) and their standard deviation at given positions in samples.
+// It returns nil if there are too few events.
+func zeroCrossingF0(filtered []float64, sampleRate int,
+	positions []float64) ([]float64, []float64) {
+	if len(filtered) < 2 {
+		return nil, nil
+	}
+	diff := make([]float64, len(filtered)-1)
+	for i := range diff {
+		diff[i] = filtered[i] - filtered[i+1]
+	}
+	negated := make([]float64, len(filtered))
+	for i, val := range filtered {
+		negated[i] = -val
+	}
+	negatedDiff := make([]float64, len(diff))
+	for i, val := range diff {
+		negatedDiff[i] = -val
+	}
+
+	var estimates [4][]float64
+	for i, x := range [][]float64{filtered, negated, negatedDiff, diff} {
+		locations, intervals := zeroCrossingIntervals(x, sampleRate)
+		if len(locations) < 2 {
+			return nil, nil
+		}
+		estimates[i] = linearInterpolation(locations, intervals, positions)
+	}
+
+	candidate := make([]float64, len(positions))
+	deviation := make([]float64, len(positions))
+	for i := range positions {
+		for j := range estimates {
+			candidate[i] += estimates[j][i]
+		}
+		candidate[i] /= float64(len(estimates))
+		for j := range estimates {
+			d := estimates[j][i] - candidate[i]
+			deviation[i] += d * d
+		}
+		deviation[i] = math.Sqrt(deviation[i] / float64(len(estimates)-1))
+	}
+
+	return candidate, deviation
+}
+
+// zeroCrossingIntervals returns locations and reciprocals of intervals of
+// negative-going zero-crossings.
+func zeroCrossingIntervals(x []float64, sampleRate int) ([]float64, []float64) {
+	var crossings []float64
+	for i := 0; i < len(x)-1; i++ {
+		if x[i] > 0.0 && x[i+1] <= 0.0 {
+			// fine location by linear interpolation
+			crossings = append(crossings, float64(i)+x[i]/(x[i]-x[i+1]))
+		}
+	}
+	if len(crossings) < 2 {
+		return nil, nil
+	}
+
+	locations := make([]float64, len(crossings)-1)
+	intervals := make([]float64, len(crossings)-1)
+	for i := range locations {
+		locations[i] = (crossings[i] + crossings[i+1]) / 2.0
+		intervals[i] = float64(sampleRate) / (crossings[i+1] - crossings[i])
+	}
+	return locations, intervals
+}
+
+// linearInterpolation performs linear interpolation of y at xi. x must be
+// sorted in ascending order. Values outside of x are held at the edges.
+func linearInterpolation(x, y, xi []float64) []float64 {
+	yi := make([]float64, len(xi))
+	k := 0
+	for i, val := range xi {
+		switch {
+		case val <= x[0]:
+			yi[i] = y[0]
+		case val >= x[len(x)-1]:
+			yi[i] = y[len(y)-1]
+		default:
+			for k > 0 && x[k] > val {
+				k--
+			}
+			for x[k+1] < val {
+				k++
+			}
+			s := (val - x[k]) / (x[k+1] - x[k])
+			yi[i] = y[k] + s*(y[k+1]-y[k])
+		}
+	}
+	return yi
+}
+
+// refineF0 refines f0 at a given position in samples based on the
+// instantaneous frequencies of harmonic components, and returns refined f0
+// and its reliability score, which is the reciprocal of the deviation of
+// f0 estimated from each harmonic.
+func refineF0(audioBuffer []float64, sampleRate int, position float64,
+	f0 float64) (float64, float64) {
+	fs := float64(sampleRate)
+	halfWindowLength := int(1.5*fs/f0 + 0.5)
+	windowLength := 2*halfWindowLength + 1
+	windowLengthInTime := float64(windowLength) / fs
+	fftSize := nextPow2(windowLength) * 2
+
+	center := int(position + 0.5)
+	mainWindowed := make([]float64, fftSize)
+	diffWindowed := make([]float64, fftSize)
+	for k := -halfWindowLength; k <= halfWindowLength; k++ {
+		index := center + k
+		if index < 0 {
+			index = 0
+		} else if index > len(audioBuffer)-1 {
+			index = len(audioBuffer) - 1
+		}
+		t := float64(k) / fs
+		arg := 2.0 * math.Pi * t / windowLengthInTime
+		mainWindow := 0.42 + 0.5*math.Cos(arg) + 0.08*math.Cos(2.0*arg)
+		// time derivative of the window
+		diffWindow := -(0.5*math.Sin(arg) + 0.16*math.Sin(2.0*arg)) *
+			2.0 * math.Pi / windowLengthInTime
+		mainWindowed[k+halfWindowLength] = audioBuffer[index] * mainWindow
+		diffWindowed[k+halfWindowLength] = audioBuffer[index] * diffWindow
+	}
+	mainSpectrum := fft.FFTReal(mainWindowed)
+	diffSpectrum := fft.FFTReal(diffWindowed)
+
+	numHarmonics := int(fs / 2.0 / f0)
+	if numHarmonics > 6 {
+		numHarmonics = 6
+	}
+	if numHarmonics < 1 {
+		return 0.0, 0.0
+	}
+
+	estimates := make([]float64, numHarmonics)
+	powers := make([]float64, numHarmonics)
+	numerator, denominator := 0.0, 0.0
+	for i := 1; i <= numHarmonics; i++ {
+		bin := int(f0*float64(i)*float64(fftSize)/fs + 0.5)
+		if bin > fftSize/2 {
+			bin = fftSize / 2
+		}
+		power := real(mainSpectrum[bin])*real(mainSpectrum[bin]) +
+			imag(mainSpectrum[bin])*imag(mainSpectrum[bin])
+		// instantaneous frequency by the time-derivative window
+		crossTerm := imag(diffSpectrum[bin] * cmplx.Conj(mainSpectrum[bin]))
+		instantaneousFrequency := float64(bin)*fs/float64(fftSize) -
+			crossTerm/(power+safeGuardMinimum)/(2.0*math.Pi)
+
+		// Power weighting suppresses harmonics that only contain leakage
+		numerator += power * instantaneousFrequency
+		denominator += power * float64(i)
+
+		estimates[i-1] = instantaneousFrequency / float64(i)
+		powers[i-1] = power
+	}
+	refined := numerator / (denominator + safeGuardMinimum)
+
+	// Power-weighted deviation of f0 estimated from each harmonic
+	deviation, sum := 0.0, 0.0
+	for i := range estimates {
+		d := estimates[i] - refined
+		deviation += powers[i] * d * d
+		sum += powers[i]
+	}
+	deviation = math.Sqrt(deviation / (sum + safeGuardMinimum))
+
+	return refined, 1.0 / (deviation + safeGuardMinimum)
+}
+
+// selectBestF0 selects the candidate that is closest to f0 extrapolated
+// from the current and past f0. It returns zero if there is no candidate
+// within the allowed range.
+func selectBestF0(currentF0, pastF0 float64, candidates []float64,
+	allowedRange float64) float64 {
+	reference := currentF0*2.0 - pastF0
+	best, minimumError := 0.0, currentF0
+	for _, candidate := range candidates {
+		err := math.Abs(reference - candidate)
+		if err < minimumError {
+			best, minimumError = candidate, err
+		}
+	}
+	if math.Abs(1.0-best/(reference+safeGuardMinimum)) > allowedRange {
+		return 0.0
+	}
+	return best
+}
+
+// fixF0Contour removes unreliable f0, removes short voiced sections and
+// extends voiced sections using f0 candidates of each frame.
+func fixF0Contour(f0Sequence []float64, candidates [][]float64,
+	voiceRangeMinimum int, allowedRange float64) []float64 {
+	numFrames := len(f0Sequence)
+	fixed := make([]float64, numFrames)
+	if numFrames <= voiceRangeMinimum {
+		return fixed
+	}
+
+	// Step 1: rapid change of f0 is regarded as unvoiced
+	base := make([]float64, numFrames)
+	copy(base[voiceRangeMinimum/2:numFrames-voiceRangeMinimum/2],
+		f0Sequence[voiceRangeMinimum/2:numFrames-voiceRangeMinimum/2])
+	step1 := make([]float64, numFrames)
+	copy(step1, base)
+	for i := voiceRangeMinimum/2 + 1; i < numFrames; i++ {
+		if math.Abs((base[i]-base[i-1])/(safeGuardMinimum+base[i])) > allowedRange {
+			step1[i] = 0.0
+		}
+	}
+
+	// Step 2: voiced sections shorter than voiceRangeMinimum are removed
+	step2 := make([]float64, numFrames)
+	copy(step2, step1)
+	center := (voiceRangeMinimum - 1) / 2
+	for i := center; i < numFrames-center; i++ {
+		for j := -center; j <= center; j++ {
+			if step1[i+j] == 0.0 {
+				step2[i] = 0.0
+				break
+			}
+		}
+	}
+
+	// Step 3: extend voiced sections forward
+	step3 := make([]float64, numFrames)
+	copy(step3, step2)
+	for i := 1; i < numFrames-1; i++ {
+		if step3[i] == 0.0 || step3[i+1] != 0.0 {
+			continue
+		}
+		for j := i; j < numFrames-1 && step3[j+1] == 0.0; j++ {
+			step3[j+1] = selectBestF0(step3[j], step3[j-1],
+				candidates[j+1], allowedRange)
+			if step3[j+1] == 0.0 {
+				break
+			}
+		}
+	}
+
+	// Step 4: extend voiced sections backward
+	copy(fixed, step3)
+	for i := numFrames - 2; i > 0; i-- {
+		if fixed[i] == 0.0 || fixed[i-1] != 0.0 {
+			continue
+		}
+		for j := i; j > 0 && fixed[j-1] == 0.0; j-- {
+			fixed[j-1] = selectBestF0(fixed[j], fixed[j+1],
+				candidates[j-1], allowedRange)
+			if fixed[j-1] == 0.0 {
+				break
+			}
+		}
+	}
+
+	return fixed
+}
@@ -11,12 +11,16 @@ const (
 	DefaultThreshold  = 0.15
 )
 
+// DefaultFrameShiftInSec is the default frame shift used in Estimate.
+const DefaultFrameShiftInSec = 0.005
+
 // YIN represents the YIN fundamental frequency estimator.
 type YIN struct {
 	Buffer     []float64 // Buffer used in the YIN analysis
 	BufferSize int
 	SampleRate int
 	Threshold  float64 // Threshold used in the absolute thresholding step
+	FrameShift int     // Frame shift used in Estimate
 }
 
 // NewYIN returns a new YIN instantce.
@@ -26,6 +30,7 @@ func NewYIN(sampleRate int) *YIN {
 	y.SampleRate = sampleRate
 	y.Buffer = make([]float64, y.BufferSize)
 	y.Threshold = DefaultThreshold
+	y.FrameShift = int(float64(sampleRate) * DefaultFrameShiftInSec)
 
 	return y
 }
@@ -61,6 +66,10 @@ func (y *YIN) ComputeF0(audioBuffer []float64) (float64, float64) {
 
 // Step 2: Difference function
 func (y *YIN) Difference(buffer []float64) {
+	for tau := range y.Buffer {
+		y.Buffer[tau] = 0.0
+	}
+
 	delta := 0.0
 	for tau := 0; tau < y.BufferSize/2; tau++ {
 		for t := 0; t < y.BufferSize/2; t++ {
@@ -76,7 +85,12 @@ func (y *YIN) CumulativeMeanNormalizedDifference() {
 	runningSum := 0.0
 	for tau := 1; tau < y.BufferSize/2; tau++ {
 		runningSum += y.Buffer[tau]
-		y.Buffer[tau] *= float64(tau) / runningSum
+		if runningSum > 0.0 {
+			y.Buffer[tau] *= float64(tau) / runningSum
+		} else {
+			// silence
+			y.Buffer[tau] = 1.0
+		}
 	}
 }
 
@@ -146,7 +160,10 @@ func (y *YIN) ParabolicInterpolation(tauEstimate int) float64 {
 		s0 := y.Buffer[x0]
 		s1 := y.Buffer[tauEstimate]
 		s2 := y.Buffer[x2]
-		betterTau = float64(tauEstimate) + (s2-s0)/(2.0*(2.0*s1-s2-s0))
+		// flat neighborhood has no vertex
+		if denom := 2.0 * (2.0*s1 - s2 - s0); denom != 0.0 {
+			betterTau = float64(tauEstimate) + (s2-s0)/denom
+		}
 	}
 
 	return betterTau
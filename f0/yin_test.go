@@ -69,6 +69,34 @@ func TestYINVeryHighFrequency(t *testing.T) {
 	}
 }
 
+func TestYINSilence(t *testing.T) {
+	y := NewYIN(16000)
+	freq, probability := y.ComputeF0(make([]float64, 1000))
+	if freq != 0.0 || probability != 0.0 {
+		t.Errorf("The estimate of silence is (%f, %f), want (0, 0).",
+			freq, probability)
+	}
+	for tau := 1; tau < y.BufferSize/2; tau++ {
+		if y.Buffer[tau] != 1.0 {
+			t.Errorf("Normalized difference of silence is %f at lag %d, want 1.",
+				y.Buffer[tau], tau)
+			break
+		}
+	}
+}
+
+func TestYINReuse(t *testing.T) {
+	sampleRate, bufferSize := 16000, 1024
+	y := NewYIN(sampleRate)
+	expected, _ := y.ComputeF0(createSin(200.0, sampleRate, bufferSize))
+
+	// The buffer is cleared before each analysis
+	y.ComputeF0(createSin(330.0, sampleRate, bufferSize))
+	if freq, _ := y.ComputeF0(createSin(200.0, sampleRate, bufferSize)); freq != expected {
+		t.Errorf("The estimate with a reused buffer is %f, want %f.", freq, expected)
+	}
+}
+
 func createSin(freq float64, sampleRate, length int) []float64 {
 	sin := make([]float64, length)
 	for i := range sin {
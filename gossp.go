@@ -174,6 +174,70 @@ func Interp1(x, y, xi []float64) []float64 {
 	return yi
 }
 
+// Spline performs natural cubic spline interpolation of y at xi, which is
+// analogous to interp1(x, y, xi, 'spline') in Matlab. x must be sorted in
+// ascending order. Outside of x, the end polynomials are extrapolated.
+func Spline(x, y, xi []float64) []float64 {
+	n := len(x)
+	yi := make([]float64, len(xi))
+	if n < 3 {
+		// Fall back to linear interpolation (or constant for a single point)
+		for i, val := range xi {
+			if n == 1 {
+				yi[i] = y[0]
+				continue
+			}
+			yi[i] = y[0] + (val-x[0])*(y[1]-y[0])/(x[1]-x[0])
+		}
+		return yi
+	}
+
+	h := make([]float64, n-1)
+	for i := range h {
+		h[i] = x[i+1] - x[i]
+	}
+
+	// Solve tridiagonal system for second derivatives (natural boundary)
+	m := make([]float64, n)
+	diag := make([]float64, n)
+	rhs := make([]float64, n)
+	diag[0], diag[n-1] = 1.0, 1.0
+	for i := 1; i < n-1; i++ {
+		diag[i] = 2.0 * (h[i-1] + h[i])
+		rhs[i] = 6.0 * ((y[i+1]-y[i])/h[i] - (y[i]-y[i-1])/h[i-1])
+	}
+	// Forward elimination
+	for i := 2; i < n-1; i++ {
+		w := h[i-1] / diag[i-1]
+		diag[i] -= w * h[i-1]
+		rhs[i] -= w * rhs[i-1]
+	}
+	// Back substitution
+	for i := n - 2; i >= 1; i-- {
+		m[i] = rhs[i]
+		if i < n-2 {
+			m[i] -= h[i] * m[i+1]
+		}
+		m[i] /= diag[i]
+	}
+
+	k := 0
+	for i, val := range xi {
+		for k > 0 && x[k] > val {
+			k--
+		}
+		for k < n-2 && x[k+1] < val {
+			k++
+		}
+		a := (x[k+1] - val) / h[k]
+		b := (val - x[k]) / h[k]
+		yi[i] = a*y[k] + b*y[k+1] +
+			((a*a*a-a)*m[k]+(b*b*b-b)*m[k+1])*h[k]*h[k]/6.0
+	}
+
+	return yi
+}
+
 // Symmetrize returns symmetrized vector given a input vector.
 func Symmetrize(x []float64) []float64 {
 	N := len(x)
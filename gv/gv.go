@@ -0,0 +1,200 @@
+// Package gv provides support for global variance (GV) of speech parameter
+// sequences, which is used to alleviate over-smoothing of generated
+// parameters.
+//
+// Reference:
+// T. Toda and K. Tokuda, "A speech parameter generation algorithm
+// considering global variance for HMM-based speech synthesis," IEICE Trans.
+// Inf. & Syst., vol. E90-D, no. 5, pp.816-824, 2007.
+package gv
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrNoSequence       = errors.New("gv: no sequences to compute statistics")
+	ErrNoVoicedSequence = errors.New("gv: no voiced sequences to compute statistics")
+)
+
+// Statistics represents the mean and variance of GV over training
+// sequences in each dimension.
+type Statistics struct {
+	Mean     []float64
+	Variance []float64
+}
+
+// NewStatistics returns GV statistics of given GV vectors. It returns
+// ErrNoSequence if no GV vectors are given.
+func NewStatistics(gvs [][]float64) (*Statistics, error) {
+	if len(gvs) == 0 {
+		return nil, ErrNoSequence
+	}
+
+	dim := len(gvs[0])
+	s := &Statistics{
+		Mean:     make([]float64, dim),
+		Variance: make([]float64, dim),
+	}
+
+	for _, gv := range gvs {
+		for d, val := range gv {
+			s.Mean[d] += val
+		}
+	}
+	for d := range s.Mean {
+		s.Mean[d] /= float64(len(gvs))
+	}
+
+	for _, gv := range gvs {
+		for d, val := range gv {
+			s.Variance[d] += (val - s.Mean[d]) * (val - s.Mean[d])
+		}
+	}
+	for d := range s.Variance {
+		s.Variance[d] /= float64(len(gvs))
+	}
+
+	return s, nil
+}
+
+// SequenceStatistics returns GV statistics of parameter sequences such as
+// mel-cepstrum sequences. It returns ErrNoSequence if no sequences are
+// given.
+func SequenceStatistics(sequences [][][]float64) (*Statistics, error) {
+	gvs := make([][]float64, len(sequences))
+	for i, sequence := range sequences {
+		gvs[i] = GV(sequence)
+	}
+	return NewStatistics(gvs)
+}
+
+// LogF0Statistics returns GV statistics (one dimension) of log-f0 of f0
+// sequences. Unvoiced frames (zero f0) are ignored, and sequences without
+// voiced frames are skipped. It returns ErrNoVoicedSequence if no sequence
+// has voiced frames.
+func LogF0Statistics(f0Sequences [][]float64) (*Statistics, error) {
+	var gvs [][]float64
+	for _, f0Sequence := range f0Sequences {
+		if _, _, n := logF0MeanVariance(f0Sequence); n == 0 {
+			continue
+		}
+		gvs = append(gvs, []float64{LogF0GV(f0Sequence)})
+	}
+	if len(gvs) == 0 {
+		return nil, ErrNoVoicedSequence
+	}
+	return NewStatistics(gvs)
+}
+
+// GV returns the global variance of a parameter sequence, which is the
+// variance over time in each dimension. An empty sequence has an empty GV.
+func GV(sequence [][]float64) []float64 {
+	mean := Mean(sequence)
+	gv := make([]float64, len(mean))
+	for _, frame := range sequence {
+		for d, val := range frame {
+			gv[d] += (val - mean[d]) * (val - mean[d])
+		}
+	}
+	for d := range gv {
+		gv[d] /= float64(len(sequence))
+	}
+	return gv
+}
+
+// Mean returns the mean over time of a parameter sequence in each
+// dimension. An empty sequence has an empty mean.
+func Mean(sequence [][]float64) []float64 {
+	if len(sequence) == 0 {
+		return []float64{}
+	}
+
+	mean := make([]float64, len(sequence[0]))
+	for _, frame := range sequence {
+		for d, val := range frame {
+			mean[d] += val
+		}
+	}
+	for d := range mean {
+		mean[d] /= float64(len(sequence))
+	}
+	return mean
+}
+
+// LogF0GV returns the global variance of log-f0 over voiced frames of an f0
+// sequence.
+func LogF0GV(f0Sequence []float64) float64 {
+	_, variance, _ := logF0MeanVariance(f0Sequence)
+	return variance
+}
+
+// Compensate returns a parameter sequence whose GV is scaled to a target
+// GV, where each dimension is scaled around its mean over time. Dimensions
+// whose target GV is not positive (e.g. power coefficients that should be
+// left unchanged) are not modified.
+func Compensate(sequence [][]float64, targetGV []float64) [][]float64 {
+	mean := Mean(sequence)
+	gv := GV(sequence)
+	scale := make([]float64, len(gv))
+	for d := range scale {
+		scale[d] = varianceScale(gv[d], targetGV[d])
+	}
+
+	compensated := make([][]float64, len(sequence))
+	for i, frame := range sequence {
+		compensated[i] = make([]float64, len(frame))
+		for d, val := range frame {
+			compensated[i][d] = scale[d]*(val-mean[d]) + mean[d]
+		}
+	}
+	return compensated
+}
+
+// CompensateLogF0 returns an f0 sequence whose GV of log-f0 is scaled to a
+// target GV. Only voiced frames are used and modified.
+func CompensateLogF0(f0Sequence []float64, targetGV float64) []float64 {
+	compensated := make([]float64, len(f0Sequence))
+	mean, variance, _ := logF0MeanVariance(f0Sequence)
+	scale := varianceScale(variance, targetGV)
+	for i, val := range f0Sequence {
+		if val > 0.0 {
+			compensated[i] = math.Exp(scale*(math.Log(val)-mean) + mean)
+		}
+	}
+	return compensated
+}
+
+// varianceScale returns the factor that scales variance to target variance.
+func varianceScale(variance, target float64) float64 {
+	if target <= 0.0 || variance <= 0.0 {
+		return 1.0
+	}
+	return math.Sqrt(target / variance)
+}
+
+// logF0MeanVariance returns the mean and variance of log-f0 and the number
+// of voiced frames of an f0 sequence.
+func logF0MeanVariance(f0Sequence []float64) (float64, float64, int) {
+	mean, n := 0.0, 0
+	for _, val := range f0Sequence {
+		if val > 0.0 {
+			mean += math.Log(val)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0.0, 0.0, 0
+	}
+	mean /= float64(n)
+
+	variance := 0.0
+	for _, val := range f0Sequence {
+		if val > 0.0 {
+			diff := math.Log(val) - mean
+			variance += diff * diff
+		}
+	}
+	return mean, variance / float64(n), n
+}
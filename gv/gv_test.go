@@ -0,0 +1,140 @@
+package gv
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func createRandomSequence(length, dim int, std float64) [][]float64 {
+	r := rand.New(rand.NewSource(1))
+	sequence := make([][]float64, length)
+	for i := range sequence {
+		sequence[i] = make([]float64, dim)
+		for d := range sequence[i] {
+			sequence[i][d] = float64(d) + std*r.NormFloat64()
+		}
+	}
+	return sequence
+}
+
+func TestGV(t *testing.T) {
+	sequence := [][]float64{{1.0, 0.0}, {3.0, 0.0}, {5.0, 0.0}}
+	gv := GV(sequence)
+	expected := []float64{8.0 / 3.0, 0.0}
+	for d := range gv {
+		if math.Abs(gv[d]-expected[d]) > 1.0e-12 {
+			t.Errorf("GV[%d] = %f, want %f.", d, gv[d], expected[d])
+		}
+	}
+}
+
+func TestNewStatistics(t *testing.T) {
+	s, err := NewStatistics([][]float64{{1.0, 2.0}, {3.0, 2.0}})
+	if err != nil {
+		t.Fatalf("NewStatistics returned error: %v", err)
+	}
+	if s.Mean[0] != 2.0 || s.Mean[1] != 2.0 {
+		t.Errorf("Mean = %v, want [2 2].", s.Mean)
+	}
+	if s.Variance[0] != 1.0 || s.Variance[1] != 0.0 {
+		t.Errorf("Variance = %v, want [1 0].", s.Variance)
+	}
+}
+
+func TestCompensate(t *testing.T) {
+	sequence := createRandomSequence(500, 4, 0.5)
+	target := []float64{0.0, 1.0, 0.25, 4.0}
+	compensated := Compensate(sequence, target)
+
+	gv := GV(compensated)
+	original := GV(sequence)
+	if math.Abs(gv[0]-original[0]) > 1.0e-12 {
+		t.Errorf("GV[0] = %f, want unchanged %f.", gv[0], original[0])
+	}
+	for d := 1; d < len(gv); d++ {
+		if math.Abs(gv[d]-target[d]) > 1.0e-10 {
+			t.Errorf("GV[%d] = %f, want %f.", d, gv[d], target[d])
+		}
+	}
+
+	// Mean is preserved
+	mean, originalMean := Mean(compensated), Mean(sequence)
+	for d := range mean {
+		if math.Abs(mean[d]-originalMean[d]) > 1.0e-10 {
+			t.Errorf("Mean[%d] = %f, want %f.", d, mean[d], originalMean[d])
+		}
+	}
+}
+
+func TestCompensateLogF0(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	f0Sequence := make([]float64, 300)
+	for i := range f0Sequence {
+		if i%10 < 3 {
+			continue // unvoiced
+		}
+		f0Sequence[i] = 150.0 * math.Exp(0.1*r.NormFloat64())
+	}
+
+	target := 0.05
+	compensated := CompensateLogF0(f0Sequence, target)
+	for i := range compensated {
+		if (compensated[i] > 0.0) != (f0Sequence[i] > 0.0) {
+			t.Fatalf("Voicing of frame %d is changed.", i)
+		}
+	}
+	if gv := LogF0GV(compensated); math.Abs(gv-target) > 1.0e-10 {
+		t.Errorf("GV of log-f0 = %f, want %f.", gv, target)
+	}
+
+	s, err := LogF0Statistics([][]float64{f0Sequence, compensated,
+		make([]float64, 10)})
+	if err != nil {
+		t.Fatalf("LogF0Statistics returned error: %v", err)
+	}
+	expected := (LogF0GV(f0Sequence) + target) / 2.0
+	if math.Abs(s.Mean[0]-expected) > 1.0e-10 {
+		t.Errorf("Mean GV of log-f0 = %f, want %f.", s.Mean[0], expected)
+	}
+}
+
+func TestEmptyInput(t *testing.T) {
+	if _, err := NewStatistics(nil); err != ErrNoSequence {
+		t.Errorf("NewStatistics(nil) error = %v, want %v.", err, ErrNoSequence)
+	}
+	if _, err := SequenceStatistics(nil); err != ErrNoSequence {
+		t.Errorf("SequenceStatistics(nil) error = %v, want %v.",
+			err, ErrNoSequence)
+	}
+	if _, err := LogF0Statistics(nil); err != ErrNoVoicedSequence {
+		t.Errorf("LogF0Statistics(nil) error = %v, want %v.",
+			err, ErrNoVoicedSequence)
+	}
+	if m := Mean(nil); len(m) != 0 {
+		t.Errorf("Mean(nil) = %v, want empty.", m)
+	}
+	if gv := GV(nil); len(gv) != 0 {
+		t.Errorf("GV(nil) = %v, want empty.", gv)
+	}
+	if c := Compensate(nil, nil); len(c) != 0 {
+		t.Errorf("Compensate(nil) = %v, want empty.", c)
+	}
+}
+
+func TestAllUnvoiced(t *testing.T) {
+	unvoiced := make([]float64, 10)
+	_, err := LogF0Statistics([][]float64{unvoiced, make([]float64, 5)})
+	if err != ErrNoVoicedSequence {
+		t.Errorf("LogF0Statistics error = %v, want %v.", err, ErrNoVoicedSequence)
+	}
+	if gv := LogF0GV(unvoiced); gv != 0.0 {
+		t.Errorf("LogF0GV = %f, want 0.", gv)
+	}
+	compensated := CompensateLogF0(unvoiced, 0.05)
+	for i, val := range compensated {
+		if val != 0.0 {
+			t.Errorf("compensated[%d] = %f, want 0.", i, val)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package lpc
+
+import (
+	"math"
+)
+
+// Burg returns LPC of a given order by Burg's method, which estimates each
+// PARCOR coefficient by minimizing the sum of forward and backward
+// prediction errors. The resulting filter is always stable.
+func Burg(x []float64, order int) (*LPC, error) {
+	if order < 1 || order >= len(x) {
+		return nil, ErrInvalidOrder
+	}
+
+	forward := make([]float64, len(x))
+	backward := make([]float64, len(x))
+	copy(forward, x)
+	copy(backward, x)
+
+	e := 0.0
+	for _, val := range x {
+		e += val * val
+	}
+	if e <= 0.0 {
+		return nil, ErrZeroEnergy
+	}
+
+	a := make([]float64, order+1)
+	parcor := make([]float64, order+1)
+	previous := make([]float64, order+1)
+	for m := 1; m <= order; m++ {
+		num, den := 0.0, 0.0
+		for n := m; n < len(x); n++ {
+			num += forward[n] * backward[n-1]
+			den += forward[n]*forward[n] + backward[n-1]*backward[n-1]
+		}
+		if den <= 0.0 {
+			return nil, ErrZeroEnergy
+		}
+		k := -2.0 * num / den
+		parcor[m] = k
+
+		copy(previous[1:m], a[1:m])
+		for i := 1; i < m; i++ {
+			a[i] = previous[i] + k*previous[m-i]
+		}
+		a[m] = k
+
+		// Update prediction errors in descending order so that
+		// backward[n-1] is that of the previous stage
+		for n := len(x) - 1; n >= m; n-- {
+			f := forward[n]
+			forward[n] = f + k*backward[n-1]
+			backward[n] = backward[n-1] + k*f
+		}
+
+		e *= 1.0 - k*k
+	}
+
+	gain := math.Sqrt(e)
+	a[0], parcor[0] = gain, gain
+	return &LPC{Coef: a, PARCOR: parcor, PredictionError: e}, nil
+}
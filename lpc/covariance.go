@@ -0,0 +1,82 @@
+package lpc
+
+import (
+	"math"
+)
+
+// Covariance returns LPC of a given order by the covariance method, which
+// minimizes the prediction error over samples from order to the end
+// without assuming that the signal is zero outside the frame. The
+// resulting filter is not guaranteed to be stable, in which case PARCOR
+// coefficients are computed up to the unstable stage and ErrUnstable is
+// not returned.
+func Covariance(x []float64, order int) (*LPC, error) {
+	if order < 1 || order >= len(x) {
+		return nil, ErrInvalidOrder
+	}
+
+	// phi[i][j] = sum_n x[n-i]x[n-j]
+	phi := make([][]float64, order+1)
+	for i := range phi {
+		phi[i] = make([]float64, order+1)
+		for j := 0; j <= i; j++ {
+			for n := order; n < len(x); n++ {
+				phi[i][j] += x[n-i] * x[n-j]
+			}
+			phi[j][i] = phi[i][j]
+		}
+	}
+	if phi[0][0] <= 0.0 {
+		return nil, ErrZeroEnergy
+	}
+
+	// Normal equation sum_j phi[i][j]a[j] = -phi[i][0] (i = 1, ..., p)
+	// solved by the Cholesky decomposition
+	p := order
+	l := make([][]float64, p)
+	for i := range l {
+		l[i] = make([]float64, p)
+	}
+	for i := 0; i < p; i++ {
+		for j := 0; j <= i; j++ {
+			sum := phi[i+1][j+1]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum <= 0.0 {
+					return nil, ErrSingular
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	y := make([]float64, p)
+	for i := 0; i < p; i++ {
+		y[i] = -phi[i+1][0]
+		for k := 0; k < i; k++ {
+			y[i] -= l[i][k] * y[k]
+		}
+		y[i] /= l[i][i]
+	}
+	a := make([]float64, p+1)
+	for i := p - 1; i >= 0; i-- {
+		val := y[i]
+		for k := i + 1; k < p; k++ {
+			val -= l[k][i] * a[k+1]
+		}
+		a[i+1] = val / l[i][i]
+	}
+
+	e := phi[0][0]
+	for j := 1; j <= p; j++ {
+		e += a[j] * phi[0][j]
+	}
+	e = math.Max(e, 0.0)
+	a[0] = math.Sqrt(e)
+
+	parcor, _ := LPC2PARCOR(a)
+	return &LPC{Coef: a, PARCOR: parcor, PredictionError: e}, nil
+}
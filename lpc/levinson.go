@@ -0,0 +1,45 @@
+package lpc
+
+import (
+	"math"
+)
+
+// LevinsonDurbin returns LPC of a given order from autocorrelation by the
+// Levinson-Durbin recursion, which is the autocorrelation method.
+func LevinsonDurbin(r []float64, order int) (*LPC, error) {
+	if order < 1 || order >= len(r) {
+		return nil, ErrInvalidOrder
+	}
+	if r[0] <= 0.0 {
+		return nil, ErrZeroEnergy
+	}
+
+	a := make([]float64, order+1)
+	parcor := make([]float64, order+1)
+	previous := make([]float64, order+1)
+	e := r[0]
+
+	for m := 1; m <= order; m++ {
+		sum := r[m]
+		for i := 1; i < m; i++ {
+			sum += a[i] * r[m-i]
+		}
+		k := -sum / e
+		parcor[m] = k
+
+		copy(previous[1:m], a[1:m])
+		for i := 1; i < m; i++ {
+			a[i] = previous[i] + k*previous[m-i]
+		}
+		a[m] = k
+
+		e *= 1.0 - k*k
+		if e <= 0.0 {
+			return nil, ErrUnstable
+		}
+	}
+
+	gain := math.Sqrt(e)
+	a[0], parcor[0] = gain, gain
+	return &LPC{Coef: a, PARCOR: parcor, PredictionError: e}, nil
+}
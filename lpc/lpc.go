@@ -0,0 +1,157 @@
+// Package lpc provides support for linear predictive coding (LPC) analysis,
+// which models a speech signal as the output of an all-pole filter
+// K/A(z), where A(z) = 1 + a(1)z^-1 + ... + a(p)z^-p.
+//
+// Following SPTK, LPC coefficients are represented as a slice whose first
+// element is the gain K followed by a(1), ..., a(p), and PARCOR
+// (reflection) coefficients are represented as K followed by k(1), ...,
+// k(p).
+package lpc
+
+import (
+	"errors"
+	"github.com/r9y9/gossp/window"
+)
+
+// Method specifies how LPC coefficients are estimated.
+type Method int
+
+const (
+	AutocorrelationMethod Method = iota // Levinson-Durbin recursion
+	CovarianceMethod                    // Cholesky decomposition
+	BurgMethod                          // Burg's method
+)
+
+var (
+	ErrInvalidOrder = errors.New("lpc: order must be positive and less than the length of a frame")
+	ErrZeroEnergy   = errors.New("lpc: signal has zero energy")
+	ErrSingular     = errors.New("lpc: covariance matrix is singular")
+	ErrUnstable     = errors.New("lpc: absolute value of PARCOR coefficient must be less than 1")
+)
+
+// LPC represents the result of LPC analysis.
+type LPC struct {
+	Coef            []float64 // K, a(1), ..., a(p)
+	PARCOR          []float64 // K, k(1), ..., k(p)
+	PredictionError float64   // Power of the prediction error (K^2)
+}
+
+// Analyzer represents LPC analysis of speech frames.
+type Analyzer struct {
+	Order       int
+	Method      Method
+	Window      []float64 // nil means the rectangular window
+	PreEmphasis float64   // 0 means no pre-emphasis
+}
+
+// NewAnalyzer returns a new Analyzer instance that estimates LPC of a
+// given order by the autocorrelation method with the Hamming window.
+func NewAnalyzer(frameLen, order int) *Analyzer {
+	return &Analyzer{
+		Order:  order,
+		Method: AutocorrelationMethod,
+		Window: window.CreateHamming(frameLen),
+	}
+}
+
+// Analyze returns LPC of a frame, which is pre-emphasized and windowed
+// before estimation.
+func (a *Analyzer) Analyze(frame []float64) (*LPC, error) {
+	if a.Order < 1 || a.Order >= len(frame) {
+		return nil, ErrInvalidOrder
+	}
+
+	x := frame
+	if a.PreEmphasis != 0.0 {
+		x = PreEmphasis(x, a.PreEmphasis)
+	}
+	if a.Window != nil {
+		x = window.Windowing(x, a.Window)
+	}
+
+	switch a.Method {
+	case CovarianceMethod:
+		return Covariance(x, a.Order)
+	case BurgMethod:
+		return Burg(x, a.Order)
+	default:
+		return LevinsonDurbin(Autocorrelation(x, a.Order), a.Order)
+	}
+}
+
+// PreEmphasis returns a signal filtered by 1 - coef z^-1.
+func PreEmphasis(x []float64, coef float64) []float64 {
+	y := make([]float64, len(x))
+	previous := 0.0
+	for i, val := range x {
+		y[i] = val - coef*previous
+		previous = val
+	}
+	return y
+}
+
+// DeEmphasis returns a signal filtered by 1/(1 - coef z^-1), which is the
+// inverse of PreEmphasis.
+func DeEmphasis(x []float64, coef float64) []float64 {
+	y := make([]float64, len(x))
+	previous := 0.0
+	for i, val := range x {
+		y[i] = val + coef*previous
+		previous = y[i]
+	}
+	return y
+}
+
+// Autocorrelation returns autocorrelation of a signal up to a given lag.
+func Autocorrelation(x []float64, maxLag int) []float64 {
+	r := make([]float64, maxLag+1)
+	for lag := range r {
+		for n := lag; n < len(x); n++ {
+			r[lag] += x[n] * x[n-lag]
+		}
+	}
+	return r
+}
+
+// LPC2PARCOR converts LPC to PARCOR coefficients by the backward
+// Levinson-Durbin recursion. It returns ErrUnstable if the all-pole filter
+// is unstable.
+func LPC2PARCOR(coef []float64) ([]float64, error) {
+	order := len(coef) - 1
+	parcor := make([]float64, order+1)
+	parcor[0] = coef[0]
+
+	a := make([]float64, order+1)
+	copy(a[1:], coef[1:])
+	previous := make([]float64, order+1)
+	for m := order; m >= 1; m-- {
+		k := a[m]
+		parcor[m] = k
+		if k >= 1.0 || k <= -1.0 {
+			return parcor, ErrUnstable
+		}
+		for i := 1; i < m; i++ {
+			previous[i] = (a[i] - k*a[m-i]) / (1.0 - k*k)
+		}
+		copy(a[1:m], previous[1:m])
+	}
+	return parcor, nil
+}
+
+// PARCOR2LPC converts PARCOR to LPC coefficients by the Levinson-Durbin
+// recursion.
+func PARCOR2LPC(parcor []float64) []float64 {
+	order := len(parcor) - 1
+	coef := make([]float64, order+1)
+	coef[0] = parcor[0]
+
+	previous := make([]float64, order+1)
+	for m := 1; m <= order; m++ {
+		copy(previous[1:m], coef[1:m])
+		for i := 1; i < m; i++ {
+			coef[i] = previous[i] + parcor[m]*previous[m-i]
+		}
+		coef[m] = parcor[m]
+	}
+	return coef
+}
@@ -0,0 +1,153 @@
+package lpc
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Coefficients of a stable all-pole filter 1/A(z)
+var testCoef = []float64{1.0, -1.3, 0.8, -0.2}
+
+func createAR(length int, excite func(n int) float64) []float64 {
+	x := make([]float64, length)
+	for n := range x {
+		x[n] = excite(n)
+		for i := 1; i < len(testCoef); i++ {
+			if n-i >= 0 {
+				x[n] -= testCoef[i] * x[n-i]
+			}
+		}
+	}
+	return x
+}
+
+func createNoiseAR(length int) []float64 {
+	r := rand.New(rand.NewSource(1))
+	return createAR(length, func(n int) float64 { return r.NormFloat64() })
+}
+
+func checkCoef(t *testing.T, method string, coef []float64, tolerance float64) {
+	for i := 1; i < len(testCoef); i++ {
+		if math.Abs(coef[i]-testCoef[i]) > tolerance {
+			t.Errorf("%s: a(%d) = %f, want %f.", method, i, coef[i], testCoef[i])
+		}
+	}
+}
+
+func TestEstimationMethods(t *testing.T) {
+	x := createNoiseAR(20000)
+	order := len(testCoef) - 1
+
+	for _, method := range []Method{AutocorrelationMethod, CovarianceMethod,
+		BurgMethod} {
+		a := &Analyzer{Order: order, Method: method}
+		l, err := a.Analyze(x)
+		if err != nil {
+			t.Fatalf("Method %d: %v", method, err)
+		}
+		checkCoef(t, "Method", l.Coef, 0.03)
+
+		// Power of the prediction error of unit variance noise
+		power := l.PredictionError / float64(len(x))
+		if math.Abs(power-1.0) > 0.05 {
+			t.Errorf("Method %d: error power = %f, want about 1.", method, power)
+		}
+		if math.Abs(l.Coef[0]*l.Coef[0]-l.PredictionError) > 1.0e-8 {
+			t.Errorf("Method %d: K^2 = %f, want %f.",
+				method, l.Coef[0]*l.Coef[0], l.PredictionError)
+		}
+	}
+}
+
+func TestCovarianceExact(t *testing.T) {
+	// Impulse response of the all-pole filter is exactly predictable
+	x := createAR(100, func(n int) float64 {
+		if n == 0 {
+			return 1.0
+		}
+		return 0.0
+	})
+	l, err := Covariance(x, len(testCoef)-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCoef(t, "Covariance", l.Coef, 1.0e-8)
+}
+
+func TestPARCOR(t *testing.T) {
+	x := createNoiseAR(1000)
+	order := 8
+	for _, estimate := range []func() (*LPC, error){
+		func() (*LPC, error) {
+			return LevinsonDurbin(Autocorrelation(x, order), order)
+		},
+		func() (*LPC, error) { return Burg(x, order) },
+	} {
+		l, err := estimate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parcor, err := LPC2PARCOR(l.Coef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		coef := PARCOR2LPC(l.PARCOR)
+		for i := range parcor {
+			if math.Abs(parcor[i]-l.PARCOR[i]) > 1.0e-10 {
+				t.Errorf("k(%d) = %f, want %f.", i, parcor[i], l.PARCOR[i])
+			}
+			if math.Abs(coef[i]-l.Coef[i]) > 1.0e-10 {
+				t.Errorf("a(%d) = %f, want %f.", i, coef[i], l.Coef[i])
+			}
+			if i > 0 && math.Abs(l.PARCOR[i]) >= 1.0 {
+				t.Errorf("|k(%d)| = %f, want < 1.", i, math.Abs(l.PARCOR[i]))
+			}
+		}
+	}
+
+	if _, err := LPC2PARCOR([]float64{1.0, 0.0, 1.5}); err != ErrUnstable {
+		t.Errorf("LPC2PARCOR returns %v, want %v.", err, ErrUnstable)
+	}
+}
+
+func TestPreEmphasis(t *testing.T) {
+	x := createNoiseAR(100)
+	y := DeEmphasis(PreEmphasis(x, 0.97), 0.97)
+	for i := range x {
+		if math.Abs(x[i]-y[i]) > 1.0e-10 {
+			t.Errorf("DeEmphasis(PreEmphasis(x))[%d] = %f, want %f.", i, y[i], x[i])
+		}
+	}
+}
+
+func TestAnalyzer(t *testing.T) {
+	frameLen := 512
+	x := createNoiseAR(frameLen)
+	a := NewAnalyzer(frameLen, 12)
+	a.PreEmphasis = 0.97
+	for _, method := range []Method{AutocorrelationMethod, CovarianceMethod,
+		BurgMethod} {
+		a.Method = method
+		l, err := a.Analyze(x)
+		if err != nil {
+			t.Fatalf("Method %d: %v", method, err)
+		}
+		if len(l.Coef) != 13 || len(l.PARCOR) != 13 {
+			t.Errorf("Method %d: length = %d, want 13.", method, len(l.Coef))
+		}
+	}
+
+	if _, err := a.Analyze(make([]float64, frameLen)); err != ErrZeroEnergy {
+		t.Errorf("Analyze returns %v, want %v.", err, ErrZeroEnergy)
+	}
+	a.Order = frameLen
+	for _, method := range []Method{AutocorrelationMethod, CovarianceMethod,
+		BurgMethod} {
+		a.Method = method
+		if _, err := a.Analyze(x); err != ErrInvalidOrder {
+			t.Errorf("Method %d: Analyze returns %v, want %v.",
+				method, err, ErrInvalidOrder)
+		}
+	}
+}
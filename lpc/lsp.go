@@ -0,0 +1,170 @@
+package lpc
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Number of grid points in [0, pi] to search roots of LSP polynomials
+const lspGridSize = 4096
+
+var ErrRootNotFound = errors.New("lpc: roots of LSP polynomials are not found")
+
+// LPC2LSP converts LPC coefficients (K, a(1), ..., a(p)) to line spectral
+// pairs (K, w(1), ..., w(p)), where w(i) are in radians in ascending order.
+// Roots of the sum and difference polynomials are searched on the grid of
+// frequency by the Chebyshev polynomial expansion and refined by the
+// bisection method.
+func LPC2LSP(coef []float64) ([]float64, error) {
+	order := len(coef) - 1
+	sum, difference := lspPolynomials(coef)
+
+	var roots []float64
+	for _, polynomial := range [][]float64{sum, difference} {
+		roots = append(roots, chebyshevRoots(polynomial)...)
+	}
+	if len(roots) != order {
+		return nil, ErrRootNotFound
+	}
+
+	lsp := make([]float64, order+1)
+	lsp[0] = coef[0]
+	copy(lsp[1:], roots)
+	sort.Float64s(lsp[1:])
+	return lsp, nil
+}
+
+// LSP2LPC converts line spectral pairs (K, w(1), ..., w(p)) to LPC
+// coefficients (K, a(1), ..., a(p)).
+func LSP2LPC(lsp []float64) []float64 {
+	order := len(lsp) - 1
+
+	// Odd-numbered and even-numbered frequencies are roots of the sum and
+	// difference polynomials respectively.
+	sum, difference := []float64{1.0}, []float64{1.0}
+	for i := 1; i <= order; i++ {
+		factor := []float64{1.0, -2.0 * math.Cos(lsp[i]), 1.0}
+		if i%2 == 1 {
+			sum = multiplyPolynomials(sum, factor)
+		} else {
+			difference = multiplyPolynomials(difference, factor)
+		}
+	}
+
+	// Trivial roots at z = -1 and z = 1
+	if order%2 == 0 {
+		sum = multiplyPolynomials(sum, []float64{1.0, 1.0})
+		difference = multiplyPolynomials(difference, []float64{1.0, -1.0})
+	} else {
+		difference = multiplyPolynomials(difference, []float64{1.0, 0.0, -1.0})
+	}
+
+	coef := make([]float64, order+1)
+	coef[0] = lsp[0]
+	for i := 1; i <= order; i++ {
+		coef[i] = (sum[i] + difference[i]) / 2.0
+	}
+	return coef
+}
+
+// lspPolynomials returns the sum and difference polynomials
+// A(z) +- z^-(p+1)A(1/z) without trivial roots, which are symmetric.
+func lspPolynomials(coef []float64) ([]float64, []float64) {
+	order := len(coef) - 1
+	a := make([]float64, order+2)
+	a[0] = 1.0
+	copy(a[1:], coef[1:])
+
+	sum := make([]float64, order+2)
+	difference := make([]float64, order+2)
+	for k := range a {
+		sum[k] = a[k] + a[order+1-k]
+		difference[k] = a[k] - a[order+1-k]
+	}
+
+	// Deflation of trivial roots by synthetic division
+	if order%2 == 0 {
+		sum = dividePolynomial(sum, -1.0, 1)
+		difference = dividePolynomial(difference, 1.0, 1)
+	} else {
+		difference = dividePolynomial(difference, 1.0, 2)
+	}
+	return sum, difference
+}
+
+// dividePolynomial returns a polynomial divided by 1 - root z^-lag.
+func dividePolynomial(polynomial []float64, root float64, lag int) []float64 {
+	quotient := make([]float64, len(polynomial)-lag)
+	for k := range quotient {
+		quotient[k] = polynomial[k]
+		if k >= lag {
+			quotient[k] += root * quotient[k-lag]
+		}
+	}
+	return quotient
+}
+
+func multiplyPolynomials(x, y []float64) []float64 {
+	product := make([]float64, len(x)+len(y)-1)
+	for i, xi := range x {
+		for j, yj := range y {
+			product[i+j] += xi * yj
+		}
+	}
+	return product
+}
+
+// chebyshevRoots returns roots in (0, pi) of a symmetric polynomial G(z)
+// of degree 2n, where G(e^jw) = e^-jnw C(cos w) and C is expanded by
+// Chebyshev polynomials.
+func chebyshevRoots(polynomial []float64) []float64 {
+	n := (len(polynomial) - 1) / 2
+	if n == 0 {
+		return nil
+	}
+	chebyshev := make([]float64, n+1)
+	chebyshev[0] = polynomial[n]
+	for j := 1; j <= n; j++ {
+		chebyshev[j] = 2.0 * polynomial[n-j]
+	}
+	evaluate := func(w float64) float64 {
+		return clenshaw(chebyshev, math.Cos(w))
+	}
+
+	var roots []float64
+	previousW, previous := 0.0, evaluate(0.0)
+	for i := 1; i <= lspGridSize && len(roots) < n; i++ {
+		w := math.Pi * float64(i) / lspGridSize
+		current := evaluate(w)
+		switch {
+		case current == 0.0:
+			roots = append(roots, w)
+			// Regarded as the value just after the sign change
+			current = -previous
+		case previous*current < 0.0:
+			low, high, lowValue := previousW, w, previous
+			for iter := 0; iter < 60; iter++ {
+				mid := (low + high) / 2.0
+				midValue := evaluate(mid)
+				if lowValue*midValue <= 0.0 {
+					high = mid
+				} else {
+					low, lowValue = mid, midValue
+				}
+			}
+			roots = append(roots, (low+high)/2.0)
+		}
+		previousW, previous = w, current
+	}
+	return roots
+}
+
+// clenshaw evaluates sum_j c[j]T_j(x) by Clenshaw's algorithm.
+func clenshaw(c []float64, x float64) float64 {
+	b1, b2 := 0.0, 0.0
+	for j := len(c) - 1; j >= 1; j-- {
+		b1, b2 = 2.0*x*b1-b2+c[j], b1
+	}
+	return x*b1 - b2 + c[0]
+}
@@ -0,0 +1,53 @@
+package lpc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLSPOfFlatSpectrum(t *testing.T) {
+	lsp, err := LPC2LSP([]float64{1.0, 0.0, 0.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{1.0, math.Pi / 3.0, 2.0 * math.Pi / 3.0}
+	for i := range expected {
+		if math.Abs(lsp[i]-expected[i]) > 1.0e-10 {
+			t.Errorf("LSP[%d] = %f, want %f.", i, lsp[i], expected[i])
+		}
+	}
+}
+
+func TestLSPConversion(t *testing.T) {
+	x := createNoiseAR(2000)
+	for _, order := range []int{1, 3, 8, 9, 16} {
+		l, err := Burg(x, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lsp, err := LPC2LSP(l.Coef)
+		if err != nil {
+			t.Fatalf("Order %d: %v", order, err)
+		}
+
+		if lsp[0] != l.Coef[0] {
+			t.Errorf("Order %d: K = %f, want %f.", order, lsp[0], l.Coef[0])
+		}
+		for i := 1; i <= order; i++ {
+			if lsp[i] <= 0.0 || lsp[i] >= math.Pi ||
+				(i > 1 && lsp[i] <= lsp[i-1]) {
+				t.Errorf("Order %d: LSP = %v, want ascending in (0, pi).",
+					order, lsp)
+				break
+			}
+		}
+
+		coef := LSP2LPC(lsp)
+		for i := range coef {
+			if math.Abs(coef[i]-l.Coef[i]) > 1.0e-8 {
+				t.Errorf("Order %d: a(%d) = %f, want %f.",
+					order, i, coef[i], l.Coef[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package mgcep
+
+import (
+	"github.com/mjibson/go-dsp/fft"
+	"math"
+)
+
+// C2ACR converts minimum phase cepstrum to autocorrelation of the
+// corresponding impulse response, r(0), ..., r(order). The power
+// spectrum exp(2 Re C(w)) is computed with the given FFT length and
+// transformed back by inverse FFT.
+func C2ACR(ceps []float64, order, fftLen int) []float64 {
+	c := make([]float64, fftLen)
+	copy(c, ceps)
+	spectrum := fft.FFTReal(c)
+
+	power := make([]float64, fftLen)
+	for k := range power {
+		power[k] = math.Exp(2.0 * real(spectrum[k]))
+	}
+	autocorrelation := fft.IFFTReal(power)
+
+	r := make([]float64, order+1)
+	for i := range r {
+		r[i] = real(autocorrelation[i])
+	}
+
+	return r
+}
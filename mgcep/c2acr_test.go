@@ -0,0 +1,23 @@
+package mgcep
+
+import (
+	"math"
+	"testing"
+)
+
+func TestC2ACR(t *testing.T) {
+	order := 10
+	c := FrequencyWarping(testMGC, 255, -0.41)
+	r := C2ACR(c, order, 1024)
+
+	h := C2IR(c, 1024)
+	for k := 0; k <= order; k++ {
+		expected := 0.0
+		for n := 0; n+k < len(h); n++ {
+			expected += h[n] * h[n+k]
+		}
+		if math.Abs(r[k]-expected) > 1.0e-8 {
+			t.Errorf("r(%d) = %f, want %f.", k, r[k], expected)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package mgcep
+
+import (
+	"github.com/r9y9/gossp/dtw"
+	"math"
+)
+
+const (
+	// DefaultDistortionFFTLen is the default FFT length used to compute
+	// log spectral distance.
+	DefaultDistortionFFTLen = 512
+)
+
+// Distortion represents per-frame and mean values of a distance measure
+// between two sequences.
+type Distortion struct {
+	Frames []float64
+	Mean   float64
+}
+
+// DistortionMeasure computes objective distance measures between a
+// reference and a target sequence of mel-generalized cepstrum.
+//
+// If Align is true, each target frame is compared with the reference frame
+// aligned by DTW, and the distortion has a value for each target frame.
+// Otherwise, frames are paired in order and the distortion has values only
+// for the first min(len(reference), len(target)) frames.
+type DistortionMeasure struct {
+	Alpha     float64 // All-pass constant (for log spectral distance)
+	Gamma     float64 // Gamma of generalized log (for log spectral distance)
+	FFTLen    int     // FFT length (for log spectral distance)
+	ExcludeC0 bool    // Exclude power coefficient c(0)
+	Align     bool    // Align the target to the reference by DTW
+
+	// DTW used for alignment, whose template is set to the reference.
+	// If nil, frame-synchronous DTW with BackwardStep of 2 is used.
+	DTW *dtw.DTW
+}
+
+// NewDistortionMeasure returns a new distortion measure for mel-cepstrum
+// that excludes c(0) and aligns sequences by DTW.
+func NewDistortionMeasure(alpha float64) *DistortionMeasure {
+	return &DistortionMeasure{
+		Alpha:     alpha,
+		Gamma:     0.0,
+		FFTLen:    DefaultDistortionFFTLen,
+		ExcludeC0: true,
+		Align:     true,
+	}
+}
+
+// MelCepstralDistortion returns mel-cepstral distortion (MCD) in dB,
+// (10/ln10) sqrt(2 sum (c(d) - c'(d))^2), for each frame.
+func (m *DistortionMeasure) MelCepstralDistortion(reference,
+	target [][]float64) Distortion {
+	mcd := dtw.MelCepstralDistance{ExcludeC0: m.ExcludeC0}
+	return m.measure(reference, target, mcd.Distance)
+}
+
+// CepstralDistance returns euclidean distance between cepstral
+// coefficients for each frame.
+func (m *DistortionMeasure) CepstralDistance(reference,
+	target [][]float64) Distortion {
+	return m.measure(reference, target, func(x, y []float64) float64 {
+		return math.Sqrt(m.squaredDistance(x, y))
+	})
+}
+
+// LogSpectralDistance returns root mean square difference of log
+// amplitude spectra in dB for each frame. The spectra are computed from
+// the cepstrum by MGC2SP with FFTLen/2+1 frequency bins.
+func (m *DistortionMeasure) LogSpectralDistance(reference,
+	target [][]float64) Distortion {
+	return m.measure(reference, target, func(x, y []float64) float64 {
+		s1 := MGC2SP(m.trim(x), m.Alpha, m.Gamma, m.FFTLen, LogAmplitudeDB)
+		s2 := MGC2SP(m.trim(y), m.Alpha, m.Gamma, m.FFTLen, LogAmplitudeDB)
+		sum := 0.0
+		for k := range s1 {
+			sum += (s1[k] - s2[k]) * (s1[k] - s2[k])
+		}
+		return math.Sqrt(sum / float64(len(s1)))
+	})
+}
+
+// measure computes the distance for each pair of frames. The target is
+// aligned to the reference if Align is true, otherwise frames are paired
+// in order up to the length of the shorter sequence.
+func (m *DistortionMeasure) measure(reference, target [][]float64,
+	distance func(x, y []float64) float64) Distortion {
+	path := m.alignment(reference, target)
+
+	d := Distortion{Frames: make([]float64, len(path))}
+	for t, index := range path {
+		d.Frames[t] = distance(reference[index], target[t])
+		d.Mean += d.Frames[t]
+	}
+	if len(path) > 0 {
+		d.Mean /= float64(len(path))
+	}
+
+	return d
+}
+
+// alignment returns the index of the reference frame for each target
+// frame.
+func (m *DistortionMeasure) alignment(reference, target [][]float64) []int {
+	if !m.Align {
+		length := len(target)
+		if len(reference) < length {
+			length = len(reference)
+		}
+		path := make([]int, length)
+		for t := range path {
+			path[t] = t
+		}
+		return path
+	}
+
+	if len(reference) == 0 || len(target) == 0 {
+		return []int{}
+	}
+
+	template := make([][]float64, len(reference))
+	for i := range reference {
+		template[i] = m.trim(reference[i])
+	}
+	sequence := make([][]float64, len(target))
+	for t := range target {
+		sequence[t] = m.trim(target[t])
+	}
+
+	d := m.DTW
+	if d == nil {
+		d = &dtw.DTW{ForwardStep: 0, BackwardStep: 2}
+	}
+	d.SetTemplate(template)
+	return d.DTW(sequence)
+}
+
+// trim returns the cepstrum with c(0) set to zero if ExcludeC0 is true.
+func (m *DistortionMeasure) trim(c []float64) []float64 {
+	if !m.ExcludeC0 {
+		return c
+	}
+	trimmed := make([]float64, len(c))
+	copy(trimmed[1:], c[1:])
+	return trimmed
+}
+
+func (m *DistortionMeasure) squaredDistance(x, y []float64) float64 {
+	start := 0
+	if m.ExcludeC0 {
+		start = 1
+	}
+	sum := 0.0
+	for i := start; i < len(x); i++ {
+		sum += (x[i] - y[i]) * (x[i] - y[i])
+	}
+	return sum
+}
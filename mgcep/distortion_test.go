@@ -0,0 +1,131 @@
+package mgcep
+
+import (
+	"github.com/r9y9/gossp/dtw"
+	"math"
+	"testing"
+)
+
+func createTestSequence(length, order int) [][]float64 {
+	sequence := make([][]float64, length)
+	for t := range sequence {
+		sequence[t] = make([]float64, order+1)
+		for i := range sequence[t] {
+			sequence[t][i] = 0.5 * math.Sin(0.3*float64(t)+float64(i)) /
+				float64(i+1)
+		}
+	}
+	return sequence
+}
+
+func TestMelCepstralDistortion(t *testing.T) {
+	reference := createTestSequence(20, 12)
+	target := make([][]float64, len(reference))
+	for i := range reference {
+		target[i] = make([]float64, len(reference[i]))
+		copy(target[i], reference[i])
+		target[i][0] += 1.0
+		target[i][1] += 0.1
+	}
+
+	m := NewDistortionMeasure(0.41)
+	m.Align = false
+	expected := 10.0 / math.Ln10 * math.Sqrt(2.0*0.01)
+	mcd := m.MelCepstralDistortion(reference, target)
+	if len(mcd.Frames) != len(reference) {
+		t.Fatalf("%d frames, want %d.", len(mcd.Frames), len(reference))
+	}
+	for i, d := range mcd.Frames {
+		if math.Abs(d-expected) > 1.0e-10 {
+			t.Errorf("Frame %d: MCD %f, want %f.", i, d, expected)
+		}
+	}
+	if math.Abs(mcd.Mean-expected) > 1.0e-10 {
+		t.Errorf("Mean MCD %f, want %f.", mcd.Mean, expected)
+	}
+
+	// Including c(0)
+	m.ExcludeC0 = false
+	expected = 10.0 / math.Ln10 * math.Sqrt(2.0*1.01)
+	mcd = m.MelCepstralDistortion(reference, target)
+	if math.Abs(mcd.Mean-expected) > 1.0e-10 {
+		t.Errorf("Mean MCD %f, want %f.", mcd.Mean, expected)
+	}
+	expected = math.Sqrt(1.01)
+	cd := m.CepstralDistance(reference, target)
+	if math.Abs(cd.Mean-expected) > 1.0e-10 {
+		t.Errorf("Mean cepstral distance %f, want %f.", cd.Mean, expected)
+	}
+}
+
+func TestDistortionAlignment(t *testing.T) {
+	reference := createTestSequence(20, 12)
+
+	// Target is time-stretched by repeating every other frame
+	target := [][]float64{}
+	for i := range reference {
+		target = append(target, reference[i])
+		if i%2 == 0 {
+			target = append(target, reference[i])
+		}
+	}
+
+	m := NewDistortionMeasure(0.41)
+	for _, d := range []Distortion{
+		m.MelCepstralDistortion(reference, target),
+		m.CepstralDistance(reference, target),
+		m.LogSpectralDistance(reference, target),
+	} {
+		if len(d.Frames) != len(target) {
+			t.Errorf("%d frames, want %d.", len(d.Frames), len(target))
+		}
+		if d.Mean > 1.0e-10 {
+			t.Errorf("Mean distortion %f, want 0.", d.Mean)
+		}
+	}
+
+	// Alignment by a step pattern
+	m.DTW = &dtw.DTW{Pattern: dtw.Symmetric2}
+	if d := m.MelCepstralDistortion(reference, target); len(d.Frames) != len(target) ||
+		d.Mean > 1.0e-10 {
+		t.Errorf("%d frames and mean MCD %f with Symmetric2, want %d and 0.",
+			len(d.Frames), d.Mean, len(target))
+	}
+
+	m.Align = false
+	mcd := m.MelCepstralDistortion(reference, target)
+	if mcd.Mean < 1.0e-3 {
+		t.Errorf("Mean MCD without alignment %f, want positive.", mcd.Mean)
+	}
+	// Only frames up to the length of the shorter sequence are compared
+	if len(mcd.Frames) != len(reference) {
+		t.Errorf("%d frames without alignment, want %d.",
+			len(mcd.Frames), len(reference))
+	}
+}
+
+func TestLogSpectralDistance(t *testing.T) {
+	reference := createTestSequence(10, 24)
+	target := make([][]float64, len(reference))
+	for i := range reference {
+		target[i] = make([]float64, len(reference[i]))
+		copy(target[i], reference[i])
+		target[i][0] += 0.5
+	}
+
+	m := NewDistortionMeasure(0.41)
+	m.Align = false
+	m.ExcludeC0 = false
+	expected := 20.0 / math.Ln10 * 0.5
+	lsd := m.LogSpectralDistance(reference, target)
+	for i, d := range lsd.Frames {
+		if math.Abs(d-expected) > 1.0e-8 {
+			t.Errorf("Frame %d: %f dB, want %f.", i, d, expected)
+		}
+	}
+
+	m.ExcludeC0 = true
+	if lsd := m.LogSpectralDistance(reference, target); lsd.Mean > 1.0e-8 {
+		t.Errorf("Mean distance %f dB, want 0.", lsd.Mean)
+	}
+}
@@ -2,7 +2,7 @@ package mgcep
 
 // GC2GC peforms Generalized cepstrum transformation.
 func GC2GC(c1 []float64, gamma1 float64, m2 int, gamma2 float64) []float64 {
-	m1 := len(c1)
+	m1 := len(c1) - 1
 	c2 := make([]float64, m2+1)
 
 	c2[0] = c1[0]
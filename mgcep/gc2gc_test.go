@@ -19,7 +19,7 @@ func TestGC2GCConsistencyWithSPTK(t *testing.T) {
 	mgc := MGCep(dummyInput, order, alpha, gamma)
 
 	testGammaSet := []float64{0.0, -1.0, -0.75, -0.5, -0.25}
-	testOrderSet := []int{15, 20, 25}
+	testOrderSet := []int{15, 20, 25, 30, 40}
 
 	tolerance := 1.0e-64
 
@@ -38,3 +38,19 @@ func TestGC2GCConsistencyWithSPTK(t *testing.T) {
 		}
 	}
 }
+
+func TestGC2GCHigherOrder(t *testing.T) {
+	// 1/(1 - r z^-1) as the normalized generalized cepstrum with gamma = -1
+	// has the cepstrum c(m) = r^m/m
+	r := 0.6
+	c := GC2GC([]float64{1.0, r}, -1.0, 10, 0.0)
+	if len(c) != 11 {
+		t.Fatalf("Length %d, want 11.", len(c))
+	}
+	for m := 1; m < len(c); m++ {
+		expected := math.Pow(r, float64(m)) / float64(m)
+		if math.Abs(c[m]-expected) > 1.0e-12 {
+			t.Errorf("c(%d) = %f, want %f.", m, c[m], expected)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package mgcep
+
+import (
+	"math"
+)
+
+// LPC2C converts linear prediction coefficients (K, a(1), ..., a(p)) to
+// cepstrum of the given order. The coefficients are regarded as the
+// normalized generalized cepstrum with gamma = -1, where c(m) = -a(m), and
+// transformed by GC2GC. The gain K becomes c(0) = log K.
+func LPC2C(lpc []float64, order int) []float64 {
+	normalized := make([]float64, len(lpc))
+	normalized[0] = lpc[0]
+	for i := 1; i < len(lpc); i++ {
+		normalized[i] = -lpc[i]
+	}
+
+	c := GC2GC(normalized, -1.0, order, 0.0)
+	c[0] = math.Log(lpc[0])
+
+	return c
+}
+
+// C2LPC converts cepstrum to linear prediction coefficients
+// (K, a(1), ..., a(p)) of the given order, which is the inverse of LPC2C.
+// The result is an approximation when the cepstrum does not come from an
+// all-pole model of the order.
+func C2LPC(ceps []float64, order int) []float64 {
+	normalized := GC2GC(GNorm(ceps, 0.0), 0.0, order, -1.0)
+
+	lpc := make([]float64, order+1)
+	lpc[0] = normalized[0]
+	for i := 1; i <= order; i++ {
+		lpc[i] = -normalized[i]
+	}
+
+	return lpc
+}
@@ -0,0 +1,31 @@
+package mgcep
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLPC2C(t *testing.T) {
+	// log(K/(1 - r z^-1)) = log K + sum r^m/m z^-m
+	r, gain := 0.6, 2.0
+	c := LPC2C([]float64{gain, -r}, 20)
+	if math.Abs(c[0]-math.Log(gain)) > 1.0e-12 {
+		t.Errorf("c(0) = %f, want %f.", c[0], math.Log(gain))
+	}
+	for m := 1; m < len(c); m++ {
+		expected := math.Pow(r, float64(m)) / float64(m)
+		if math.Abs(c[m]-expected) > 1.0e-12 {
+			t.Errorf("c(%d) = %f, want %f.", m, c[m], expected)
+		}
+	}
+}
+
+func TestC2LPC(t *testing.T) {
+	lpc := []float64{0.8, -0.9, 0.64, -0.2}
+	result := C2LPC(LPC2C(lpc, 200), len(lpc)-1)
+	for i := range lpc {
+		if math.Abs(result[i]-lpc[i]) > 1.0e-10 {
+			t.Errorf("a(%d) = %f, want %f.", i, result[i], lpc[i])
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package mgcep
+
+import (
+	"github.com/r9y9/gossp/lpc"
+)
+
+// MGC2LSP converts mel-generalized cepstrum to mel-generalized line
+// spectral pairs (K, w(1), ..., w(m)). The cepstrum is converted to the
+// normalized generalized cepstrum with gamma = -1, which corresponds to
+// the all-pole filter, and its LSP are computed by lpc.LPC2LSP. For
+// mel-cepstrum, frequencies of LSP are on the warped frequency axis.
+func MGC2LSP(mgc []float64, gamma float64) ([]float64, error) {
+	order := len(mgc) - 1
+	normalized := GNorm(mgc, gamma)
+	if gamma != -1.0 {
+		normalized = GC2GC(normalized, gamma, order, -1.0)
+	}
+
+	// A(z) = 1 + gamma*c(1)z^-1 + ... + gamma*c(m)z^-m with gamma = -1
+	coef := make([]float64, order+1)
+	coef[0] = normalized[0]
+	for i := 1; i <= order; i++ {
+		coef[i] = -normalized[i]
+	}
+
+	return lpc.LPC2LSP(coef)
+}
+
+// LSP2MGC converts mel-generalized line spectral pairs to mel-generalized
+// cepstrum, which is the inverse of MGC2LSP.
+func LSP2MGC(lsp []float64, gamma float64) []float64 {
+	order := len(lsp) - 1
+	coef := lpc.LSP2LPC(lsp)
+
+	normalized := make([]float64, order+1)
+	normalized[0] = coef[0]
+	for i := 1; i <= order; i++ {
+		normalized[i] = -coef[i]
+	}
+	if gamma != -1.0 {
+		normalized = GC2GC(normalized, -1.0, order, gamma)
+	}
+
+	return IGNorm(normalized, gamma)
+}
@@ -0,0 +1,42 @@
+package mgcep
+
+import (
+	"math"
+	"testing"
+)
+
+var testMGC = []float64{-0.5, 0.6, 0.2, -0.15, 0.1, -0.05, 0.02}
+
+func TestMGC2LSP(t *testing.T) {
+	for _, gamma := range []float64{-1.0, -0.5, -1.0 / 3.0} {
+		lsp, err := MGC2LSP(testMGC, gamma)
+		if err != nil {
+			t.Fatalf("Gamma %f: %v", gamma, err)
+		}
+		for i := 1; i < len(lsp); i++ {
+			if lsp[i] <= 0.0 || lsp[i] >= math.Pi ||
+				(i > 1 && lsp[i] <= lsp[i-1]) {
+				t.Errorf("Gamma %f: LSP = %v, want ascending in (0, pi).",
+					gamma, lsp)
+				break
+			}
+		}
+
+		// The gain is that of the normalized cepstrum
+		if gain := GNorm(testMGC, gamma)[0]; math.Abs(lsp[0]-gain) > 1.0e-10 {
+			t.Errorf("Gamma %f: K = %f, want %f.", gamma, lsp[0], gain)
+		}
+	}
+
+	// Exact inversion for gamma = -1
+	lsp, err := MGC2LSP(testMGC, -1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgc := LSP2MGC(lsp, -1.0)
+	for i := range mgc {
+		if math.Abs(mgc[i]-testMGC[i]) > 1.0e-8 {
+			t.Errorf("LSP2MGC[%d] = %f, want %f.", i, mgc[i], testMGC[i])
+		}
+	}
+}
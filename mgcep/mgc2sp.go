@@ -0,0 +1,63 @@
+package mgcep
+
+import (
+	"github.com/mjibson/go-dsp/fft"
+	"math"
+	"math/cmplx"
+)
+
+// SpectrumType specifies the representation of spectrum returned by
+// MGC2SP.
+type SpectrumType int
+
+const (
+	LogAmplitude   SpectrumType = iota // Natural log amplitude
+	LogAmplitudeDB                     // 20 log10 amplitude
+	Amplitude                          // Amplitude
+	Power                              // Power
+)
+
+// MGC2SP converts mel-generalized cepstrum to spectrum of the given FFT
+// length and returns fftLen/2+1 frequency bins from 0 to pi. The
+// cepstrum is first converted back to the linear frequency axis, and the
+// log amplitude is computed as Re C(w) for gamma = 0, otherwise
+// log|1 + gamma*C(w)|/gamma.
+func MGC2SP(mgc []float64, alpha, gamma float64, fftLen int,
+	spectrumType SpectrumType) []float64 {
+	c := FrequencyWarping(mgc, fftLen-1, -alpha)
+	if gamma != 0.0 {
+		for i := range c {
+			c[i] *= gamma
+		}
+		c[0] += 1.0
+	}
+	spectrum := fft.FFTReal(c)
+
+	result := make([]float64, fftLen/2+1)
+	for k := range result {
+		logAmp := real(spectrum[k])
+		if gamma != 0.0 {
+			logAmp = math.Log(cmplx.Abs(spectrum[k])) / gamma
+		}
+
+		switch spectrumType {
+		case LogAmplitudeDB:
+			result[k] = 20.0 / math.Ln10 * logAmp
+		case Amplitude:
+			result[k] = math.Exp(logAmp)
+		case Power:
+			result[k] = math.Exp(2.0 * logAmp)
+		default:
+			result[k] = logAmp
+		}
+	}
+
+	return result
+}
+
+// MC2SP converts mel-cepstrum to spectrum, which is equivalent to MGC2SP
+// with gamma = 0.
+func MC2SP(mc []float64, alpha float64, fftLen int,
+	spectrumType SpectrumType) []float64 {
+	return MGC2SP(mc, alpha, 0.0, fftLen, spectrumType)
+}
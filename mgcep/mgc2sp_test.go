@@ -0,0 +1,67 @@
+package mgcep
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// warpedFrequency returns the phase of the all-pass function at w.
+func warpedFrequency(w, alpha float64) float64 {
+	return w + 2.0*math.Atan(alpha*math.Sin(w)/(1.0-alpha*math.Cos(w)))
+}
+
+func TestMC2SP(t *testing.T) {
+	fftLen, alpha := 512, 0.41
+	logAmp := MC2SP(testMGC, alpha, fftLen, LogAmplitude)
+	if len(logAmp) != fftLen/2+1 {
+		t.Fatalf("Length %d, want %d.", len(logAmp), fftLen/2+1)
+	}
+
+	for k := range logAmp {
+		w := warpedFrequency(2.0*math.Pi*float64(k)/float64(fftLen), alpha)
+		expected := 0.0
+		for m := range testMGC {
+			expected += testMGC[m] * math.Cos(w*float64(m))
+		}
+		if math.Abs(logAmp[k]-expected) > 1.0e-8 {
+			t.Errorf("Bin %d: %f, want %f.", k, logAmp[k], expected)
+		}
+	}
+}
+
+func TestMGC2SP(t *testing.T) {
+	fftLen := 256
+
+	// gamma = -1 corresponds to the all-pole filter K/A(z)
+	lpc := []float64{0.5, -0.9, 0.64}
+	c := []float64{lpc[0], -lpc[1], -lpc[2]}
+	amplitude := MGC2SP(IGNorm(c, -1.0), 0.0, -1.0, fftLen, Amplitude)
+	for k := range amplitude {
+		w := 2.0 * math.Pi * float64(k) / float64(fftLen)
+		a := complex(1.0, 0.0)
+		for i := 1; i < len(lpc); i++ {
+			a += complex(lpc[i], 0.0) * cmplx.Exp(complex(0.0, -w*float64(i)))
+		}
+		expected := lpc[0] / cmplx.Abs(a)
+		if math.Abs(amplitude[k]-expected) > 1.0e-10 {
+			t.Errorf("Bin %d: %f, want %f.", k, amplitude[k], expected)
+		}
+	}
+
+	// Consistency between spectrum types
+	mgc := testMGC
+	logAmp := MGC2SP(mgc, 0.35, -0.5, fftLen, LogAmplitude)
+	db := MGC2SP(mgc, 0.35, -0.5, fftLen, LogAmplitudeDB)
+	power := MGC2SP(mgc, 0.35, -0.5, fftLen, Power)
+	for k := range logAmp {
+		if math.Abs(db[k]-20.0*logAmp[k]/math.Ln10) > 1.0e-10 {
+			t.Errorf("Bin %d: %f dB, want %f.", k, db[k],
+				20.0*logAmp[k]/math.Ln10)
+		}
+		if math.Abs(power[k]-math.Exp(2.0*logAmp[k])) > 1.0e-10 {
+			t.Errorf("Bin %d: power %f, want %f.", k, power[k],
+				math.Exp(2.0*logAmp[k]))
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package mgcep
+
+import (
+	"github.com/mjibson/go-dsp/fft"
+	"math"
+)
+
+// SP2MGC fits mel-generalized cepstrum to a power spectrum of
+// fftLen/2+1 frequency bins. Unlike MGCep, it does not use UELS; the
+// minimum phase cepstrum of the spectrum is computed directly and then
+// transformed by MGC2MGC, so that the result equals the input of MGC2SP
+// when the spectrum comes from it.
+func SP2MGC(powerSpectrum []float64, order int, alpha, gamma float64) []float64 {
+	fftLen := (len(powerSpectrum) - 1) * 2
+	logPower := make([]float64, fftLen)
+	for k, val := range powerSpectrum {
+		logPower[k] = math.Log(val)
+	}
+	for k := len(powerSpectrum); k < fftLen; k++ {
+		logPower[k] = logPower[fftLen-k]
+	}
+
+	// Real cepstrum of log power is twice that of log amplitude, and the
+	// minimum phase cepstrum doubles the causal part of the latter.
+	realCeps := fft.IFFTReal(logPower)
+	ceps := make([]float64, fftLen/2+1)
+	ceps[0] = real(realCeps[0]) / 2.0
+	for i := 1; i < len(ceps); i++ {
+		ceps[i] = real(realCeps[i])
+	}
+	ceps[fftLen/2] /= 2.0
+
+	return MGC2MGC(ceps, 0.0, 0.0, order, alpha, gamma)
+}
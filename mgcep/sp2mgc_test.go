@@ -0,0 +1,22 @@
+package mgcep
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSP2MGC(t *testing.T) {
+	fftLen := 1024
+	for _, alpha := range []float64{0.0, 0.41} {
+		for _, gamma := range []float64{0.0, -1.0 / 3.0, -0.5} {
+			power := MGC2SP(testMGC, alpha, gamma, fftLen, Power)
+			mgc := SP2MGC(power, len(testMGC)-1, alpha, gamma)
+			for i := range mgc {
+				if math.Abs(mgc[i]-testMGC[i]) > 1.0e-4 {
+					t.Errorf("Alpha %f, gamma %f: c(%d) = %f, want %f.",
+						alpha, gamma, i, mgc[i], testMGC[i])
+				}
+			}
+		}
+	}
+}
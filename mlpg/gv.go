@@ -0,0 +1,135 @@
+package mlpg
+
+// Reference:
+// T. Toda and K. Tokuda, "A speech parameter generation algorithm
+// considering global variance for HMM-based speech synthesis," IEICE Trans.
+// Inf. & Syst., vol. E90-D, no. 5, pp.816-824, 2007.
+
+import (
+	"github.com/r9y9/gossp/gv"
+	"math"
+)
+
+const (
+	// DefaultGVIterations is the number of Newton-Raphson iterations to
+	// refine a sequence with GV.
+	DefaultGVIterations = 20
+	// DefaultGVStepSize is the initial step size of the iterations, which
+	// is halved whenever a step does not increase the objective.
+	DefaultGVStepSize = 1.0
+)
+
+// GenerateWithGV returns the static feature sequence generated by Generate
+// and refined to maximize the likelihood that also considers GV given GV
+// statistics. Dimensions whose GV variance is not positive are generated
+// without GV, and small GV variances are floored as in Generate.
+func GenerateWithGV(means, variances [][]float64, windows []Window,
+	stats *gv.Statistics) [][]float64 {
+	T := len(means)
+	if T == 0 {
+		return [][]float64{}
+	}
+	dim := len(means[0]) / len(windows)
+
+	sequence := make([][]float64, T)
+	for t := range sequence {
+		sequence[t] = make([]float64, dim)
+	}
+	for d := 0; d < dim; d++ {
+		band, r := normalEquation(means, variances, windows, d)
+		c := solveBanded(band, r)
+		if stats.Variance[d] > 0.0 {
+			c = refineWithGV(c, band, r, stats.Mean[d],
+				precisionOf(stats.Variance[d]), 1.0/float64(len(windows)*T))
+		}
+		for t := range sequence {
+			sequence[t][d] = c[t]
+		}
+	}
+	return sequence
+}
+
+// refineWithGV maximizes weight*(-c'Rc/2 + c'r) - precision*(v(c)-gvMean)^2/2
+// by the Newton-Raphson method with the diagonal Hessian, where v(c) is the
+// GV of c. The initial value is the ML solution scaled to gvMean.
+func refineWithGV(ml []float64, band [][]float64, r []float64,
+	gvMean, precision, weight float64) []float64 {
+	T := len(ml)
+	c := make([]float64, T)
+	mean := average(ml)
+	scale := math.Sqrt(gvMean / math.Max(variance(ml, mean), 1.0e-12))
+	for t, val := range ml {
+		c[t] = scale*(val-mean) + mean
+	}
+
+	objective := func(c []float64) float64 {
+		rc := bandMultiply(band, c)
+		l := 0.0
+		for t := range c {
+			l += -0.5*c[t]*rc[t] + c[t]*r[t]
+		}
+		diff := variance(c, average(c)) - gvMean
+		return weight*l - 0.5*precision*diff*diff
+	}
+
+	stepSize := DefaultGVStepSize
+	current := objective(c)
+	next := make([]float64, T)
+	for iter := 0; iter < DefaultGVIterations; iter++ {
+		mean = average(c)
+		v := variance(c, mean)
+		rc := bandMultiply(band, c)
+
+		for t := range c {
+			dev := c[t] - mean
+			grad := weight*(r[t]-rc[t]) -
+				precision*(v-gvMean)*2.0*dev/float64(T)
+			hess := -weight*band[t][0] - precision*2.0/float64(T)*
+				((v-gvMean)*(1.0-1.0/float64(T))+2.0*dev*dev/float64(T))
+			if hess < 0.0 {
+				next[t] = c[t] - stepSize*grad/hess
+			} else {
+				next[t] = c[t] + stepSize*grad/math.Abs(weight*band[t][0])
+			}
+		}
+
+		if candidate := objective(next); candidate > current {
+			copy(c, next)
+			current = candidate
+		} else {
+			stepSize /= 2.0
+		}
+	}
+
+	return c
+}
+
+// bandMultiply returns Rc, where band[t][j] is the element (t, t+j) of the
+// symmetric matrix R.
+func bandMultiply(band [][]float64, c []float64) []float64 {
+	rc := make([]float64, len(c))
+	for t := range c {
+		rc[t] += band[t][0] * c[t]
+		for j := 1; j < len(band[t]) && t+j < len(c); j++ {
+			rc[t] += band[t][j] * c[t+j]
+			rc[t+j] += band[t][j] * c[t]
+		}
+	}
+	return rc
+}
+
+func average(c []float64) float64 {
+	sum := 0.0
+	for _, val := range c {
+		sum += val
+	}
+	return sum / float64(len(c))
+}
+
+func variance(c []float64, mean float64) float64 {
+	v := 0.0
+	for _, val := range c {
+		v += (val - mean) * (val - mean)
+	}
+	return v / float64(len(c))
+}
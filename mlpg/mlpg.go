@@ -0,0 +1,210 @@
+// Package mlpg provides support for maximum likelihood parameter generation
+// (MLPG), which generates smooth parameter trajectories from means and
+// variances of static and dynamic features.
+//
+// Reference:
+// K. Tokuda, T. Yoshimura, T. Masuko, T. Kobayashi and T. Kitamura,
+// "Speech parameter generation algorithms for HMM-based speech synthesis,"
+// Proc. ICASSP, pp.1315-1318, 2000.
+package mlpg
+
+import (
+	"math"
+)
+
+// Variances are floored at varianceFloor so that a zero variance gives a
+// large but finite precision.
+const varianceFloor = 1.0e-10
+
+// Window represents coefficients to compute a static or dynamic feature
+// from neighboring frames. The length must be odd and the center
+// coefficient corresponds to the current frame.
+type Window []float64
+
+var (
+	StaticWindow     = Window{1.0}
+	DeltaWindow      = Window{-0.5, 0.0, 0.5}
+	DeltaDeltaWindow = Window{1.0, -2.0, 1.0}
+)
+
+// DefaultWindows returns windows of static, delta and delta-delta features.
+func DefaultWindows() []Window {
+	return []Window{StaticWindow, DeltaWindow, DeltaDeltaWindow}
+}
+
+// RegressionWindow returns a window of the delta feature computed by
+// linear regression over [-halfWidth, halfWidth] frames.
+func RegressionWindow(halfWidth int) Window {
+	w := make(Window, 2*halfWidth+1)
+	norm := 0.0
+	for k := -halfWidth; k <= halfWidth; k++ {
+		norm += float64(k * k)
+	}
+	for k := -halfWidth; k <= halfWidth; k++ {
+		w[k+halfWidth] = float64(k) / norm
+	}
+	return w
+}
+
+// HalfWidth returns the number of frames on each side of the current frame.
+func (w Window) HalfWidth() int {
+	return len(w) / 2
+}
+
+// AppendDelta returns a sequence of static and dynamic features computed by
+// windows. The features of each window are concatenated in the order of
+// windows, so that the dimension is len(windows) times that of the input.
+// Frames out of range are regarded as zero, which is consistent with
+// Generate.
+func AppendDelta(sequence [][]float64, windows []Window) [][]float64 {
+	T := len(sequence)
+	if T == 0 {
+		return [][]float64{}
+	}
+	dim := len(sequence[0])
+
+	features := make([][]float64, T)
+	for t := range features {
+		features[t] = make([]float64, dim*len(windows))
+		for i, w := range windows {
+			h := w.HalfWidth()
+			for k, coef := range w {
+				tau := t + k - h
+				if tau < 0 || tau >= T || coef == 0.0 {
+					continue
+				}
+				for d := 0; d < dim; d++ {
+					features[t][i*dim+d] += coef * sequence[tau][d]
+				}
+			}
+		}
+	}
+	return features
+}
+
+// Generate returns the static feature sequence that maximizes the
+// likelihood of the static and dynamic features given means and diagonal
+// variances of each frame. means[t] and variances[t] are arranged in the
+// same way as the output of AppendDelta. An infinite variance means that
+// the feature has no influence on the generated sequence, and a zero
+// variance is floored to a small positive value.
+func Generate(means, variances [][]float64, windows []Window) [][]float64 {
+	T := len(means)
+	if T == 0 {
+		return [][]float64{}
+	}
+	dim := len(means[0]) / len(windows)
+
+	sequence := make([][]float64, T)
+	for t := range sequence {
+		sequence[t] = make([]float64, dim)
+	}
+	for d := 0; d < dim; d++ {
+		band, r := normalEquation(means, variances, windows, d)
+		c := solveBanded(band, r)
+		for t := range sequence {
+			sequence[t][d] = c[t]
+		}
+	}
+	return sequence
+}
+
+// normalEquation returns W^T U^-1 W (in the band form) and W^T U^-1 mu of
+// the d-th dimension, where W is the matrix of windows, U is the diagonal
+// covariance and mu is the mean vector. band[t][j] is the element (t, t+j).
+func normalEquation(means, variances [][]float64, windows []Window,
+	d int) ([][]float64, []float64) {
+	T := len(means)
+	dim := len(means[0]) / len(windows)
+	bandWidth := 0
+	for _, w := range windows {
+		if 2*w.HalfWidth() > bandWidth {
+			bandWidth = 2 * w.HalfWidth()
+		}
+	}
+
+	band := make([][]float64, T)
+	for t := range band {
+		band[t] = make([]float64, bandWidth+1)
+	}
+	r := make([]float64, T)
+
+	for i, w := range windows {
+		h := w.HalfWidth()
+		for t := 0; t < T; t++ {
+			precision := precisionOf(variances[t][i*dim+d])
+			mean := means[t][i*dim+d]
+			for k, coef := range w {
+				tau := t + k - h
+				if tau < 0 || tau >= T || coef == 0.0 {
+					continue
+				}
+				r[tau] += precision * mean * coef
+				for l := k; l < len(w); l++ {
+					sigma := t + l - h
+					if sigma >= T {
+						break
+					}
+					band[tau][sigma-tau] += precision * coef * w[l]
+				}
+			}
+		}
+	}
+	return band, r
+}
+
+// solveBanded solves the symmetric positive definite banded system by the
+// LDL^T decomposition, where band[t][j] is the element (t, t+j).
+func solveBanded(band [][]float64, r []float64) []float64 {
+	T := len(band)
+	bandWidth := len(band[0]) - 1
+
+	// lower[t][j] is the element (t+j, t) of L
+	lower := make([][]float64, T)
+	diag := make([]float64, T)
+	for t := 0; t < T; t++ {
+		lower[t] = make([]float64, bandWidth+1)
+		lower[t][0] = 1.0
+
+		diag[t] = band[t][0]
+		for k := max(0, t-bandWidth); k < t; k++ {
+			diag[t] -= lower[k][t-k] * lower[k][t-k] * diag[k]
+		}
+		for j := 1; j <= bandWidth && t+j < T; j++ {
+			val := band[t][j]
+			for k := max(0, t+j-bandWidth); k < t; k++ {
+				val -= lower[k][t+j-k] * lower[k][t-k] * diag[k]
+			}
+			lower[t][j] = val / diag[t]
+		}
+	}
+
+	// Forward substitution (L y = r) and backward substitution
+	// (D L^T c = y)
+	c := make([]float64, T)
+	copy(c, r)
+	for t := 0; t < T; t++ {
+		for k := max(0, t-bandWidth); k < t; k++ {
+			c[t] -= lower[k][t-k] * c[k]
+		}
+	}
+	for t := T - 1; t >= 0; t-- {
+		c[t] /= diag[t]
+		for j := 1; j <= bandWidth && t+j < T; j++ {
+			c[t] -= lower[t][j] * c[t+j]
+		}
+	}
+	return c
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// precisionOf returns the inverse of a variance floored at varianceFloor.
+func precisionOf(variance float64) float64 {
+	return 1.0 / math.Max(variance, varianceFloor)
+}
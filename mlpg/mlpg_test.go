@@ -0,0 +1,177 @@
+package mlpg
+
+import (
+	"github.com/r9y9/gossp/gv"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func createTrajectory(T, dim int) [][]float64 {
+	sequence := make([][]float64, T)
+	for t := range sequence {
+		sequence[t] = make([]float64, dim)
+		for d := range sequence[t] {
+			sequence[t][d] = math.Sin(2.0*math.Pi*float64(t*(d+1))/float64(T)) +
+				float64(d)
+		}
+	}
+	return sequence
+}
+
+func constantVariances(T, dim int, val float64) [][]float64 {
+	variances := make([][]float64, T)
+	for t := range variances {
+		variances[t] = make([]float64, dim)
+		for d := range variances[t] {
+			variances[t][d] = val
+		}
+	}
+	return variances
+}
+
+func TestAppendDelta(t *testing.T) {
+	sequence := [][]float64{{1.0}, {2.0}, {4.0}}
+	features := AppendDelta(sequence, DefaultWindows())
+	expected := [][]float64{
+		{1.0, 1.0, 0.0},
+		{2.0, 1.5, 1.0},
+		{4.0, -1.0, -6.0},
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(features[i][j]-expected[i][j]) > 1.0e-12 {
+				t.Errorf("Features[%d][%d] = %f, want %f.",
+					i, j, features[i][j], expected[i][j])
+			}
+		}
+	}
+}
+
+func TestRegressionWindow(t *testing.T) {
+	w := RegressionWindow(1)
+	for i := range w {
+		if math.Abs(w[i]-DeltaWindow[i]) > 1.0e-12 {
+			t.Errorf("RegressionWindow(1)[%d] = %f, want %f.",
+				i, w[i], DeltaWindow[i])
+		}
+	}
+}
+
+func TestGenerateConsistentFeatures(t *testing.T) {
+	T, dim := 100, 3
+	windows := []Window{StaticWindow, RegressionWindow(2), DeltaDeltaWindow}
+	sequence := createTrajectory(T, dim)
+	means := AppendDelta(sequence, windows)
+
+	// Consistent static and dynamic features are generated as they are
+	r := rand.New(rand.NewSource(1))
+	variances := constantVariances(T, dim*len(windows), 1.0)
+	for t := range variances {
+		for j := range variances[t] {
+			variances[t][j] = 0.1 + r.Float64()
+		}
+	}
+	generated := Generate(means, variances, windows)
+	for i := range sequence {
+		for d := range sequence[i] {
+			if math.Abs(generated[i][d]-sequence[i][d]) > 1.0e-8 {
+				t.Errorf("Generated[%d][%d] = %f, want %f.",
+					i, d, generated[i][d], sequence[i][d])
+			}
+		}
+	}
+}
+
+func TestGenerateSmoothness(t *testing.T) {
+	T, dim := 200, 1
+	windows := DefaultWindows()
+
+	// Step-wise static means with zero dynamic means
+	means := make([][]float64, T)
+	for i := range means {
+		means[i] = make([]float64, dim*len(windows))
+		means[i][0] = float64(i / 50)
+	}
+	variances := constantVariances(T, dim*len(windows), 1.0)
+	for i := range variances {
+		variances[i][0] = 0.01
+	}
+	generated := Generate(means, variances, windows)
+
+	// Generated trajectory is smoother than the means
+	roughness := func(x []float64) float64 {
+		sum := 0.0
+		for i := 1; i < len(x); i++ {
+			sum += (x[i] - x[i-1]) * (x[i] - x[i-1])
+		}
+		return sum
+	}
+	static := make([]float64, T)
+	c := make([]float64, T)
+	for i := range static {
+		static[i] = means[i][0]
+		c[i] = generated[i][0]
+	}
+	if roughness(c) >= roughness(static) {
+		t.Errorf("Roughness of generated trajectory = %f, want < %f.",
+			roughness(c), roughness(static))
+	}
+	if math.Abs(c[25]-static[25]) > 0.05 {
+		t.Errorf("Generated[25] = %f, want about %f.", c[25], static[25])
+	}
+}
+
+func TestGenerateZeroVariance(t *testing.T) {
+	T, dim := 50, 1
+	windows := DefaultWindows()
+	sequence := createTrajectory(T, dim)
+	means := AppendDelta(sequence, windows)
+	variances := constantVariances(T, dim*len(windows), 1.0)
+
+	// Zero variance of the static feature pins the frame to its mean
+	means[10][0] += 1.0
+	variances[10][0] = 0.0
+	generated := Generate(means, variances, windows)
+	for i := range generated {
+		if math.IsNaN(generated[i][0]) || math.IsInf(generated[i][0], 0) {
+			t.Fatalf("Generated[%d][0] = %f, want finite.", i, generated[i][0])
+		}
+	}
+	if math.Abs(generated[10][0]-means[10][0]) > 1.0e-6 {
+		t.Errorf("Generated[10][0] = %f, want %f.", generated[10][0], means[10][0])
+	}
+}
+
+func TestGenerateWithGV(t *testing.T) {
+	T, dim := 200, 2
+	windows := DefaultWindows()
+	sequence := createTrajectory(T, dim)
+	means := AppendDelta(sequence, windows)
+	variances := constantVariances(T, dim*len(windows), 1.0)
+
+	// Over-smoothed static means
+	for i := range means {
+		for d := 0; d < dim; d++ {
+			means[i][d] = 0.5*(means[i][d]-float64(d)) + float64(d)
+		}
+	}
+	ml := Generate(means, variances, windows)
+
+	target := gv.GV(sequence)
+	stats := &gv.Statistics{
+		Mean:     target,
+		Variance: []float64{0.0, 1.0e-4},
+	}
+	generated := GenerateWithGV(means, variances, windows, stats)
+
+	mlGV, generatedGV := gv.GV(ml), gv.GV(generated)
+	if math.Abs(generatedGV[0]-mlGV[0]) > 1.0e-12 {
+		t.Errorf("GV[0] = %f, want unchanged %f.", generatedGV[0], mlGV[0])
+	}
+	if math.Abs(generatedGV[1]-target[1]) >= math.Abs(mlGV[1]-target[1]) ||
+		math.Abs(generatedGV[1]-target[1]) > 0.05*target[1] {
+		t.Errorf("GV[1] = %f (%f without GV), want about %f.",
+			generatedGV[1], mlGV[1], target[1])
+	}
+}
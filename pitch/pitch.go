@@ -0,0 +1,51 @@
+// Package pitch provides the representation of f0 contours and the
+// interface of f0 estimators. It is kept free of cgo so that packages
+// which consume f0 contours do not depend on SPTK.
+package pitch
+
+// Contour represents an f0 contour estimated by an Estimator. The n-th
+// frame is centered at n*FrameShift samples.
+type Contour struct {
+	SampleRate int
+	FrameShift int
+	Times      []float64 // Center time of each frame in seconds
+	F0         []float64 // f0 in Hz, which is zero for unvoiced frames
+	Confidence []float64 // Confidence of each estimate in [0, 1]
+	Voiced     []bool
+}
+
+// Estimator is the interface implemented by fundamental frequency
+// estimators, which estimate an f0 contour from an audio buffer.
+type Estimator interface {
+	Estimate(audioBuffer []float64) *Contour
+}
+
+// NewContour returns a new contour given an f0 sequence whose n-th element
+// corresponds to the time n*frameShift. Voiced frames (non-zero f0) have
+// confidence of one and unvoiced frames have zero.
+func NewContour(f0Sequence []float64, sampleRate, frameShift int) *Contour {
+	c := &Contour{
+		SampleRate: sampleRate,
+		FrameShift: frameShift,
+		Times:      make([]float64, len(f0Sequence)),
+		F0:         make([]float64, len(f0Sequence)),
+		Confidence: make([]float64, len(f0Sequence)),
+		Voiced:     make([]bool, len(f0Sequence)),
+	}
+
+	copy(c.F0, f0Sequence)
+	for i, val := range f0Sequence {
+		c.Times[i] = float64(i*frameShift) / float64(sampleRate)
+		if val > 0.0 {
+			c.Confidence[i] = 1.0
+			c.Voiced[i] = true
+		}
+	}
+
+	return c
+}
+
+// Len returns the number of frames.
+func (c *Contour) Len() int {
+	return len(c.F0)
+}
@@ -0,0 +1,24 @@
+package pitch
+
+import (
+	"testing"
+)
+
+func TestNewContour(t *testing.T) {
+	f0Sequence := []float64{0, 100, 110, 0}
+	c := NewContour(f0Sequence, 16000, 80)
+	if c.SampleRate != 16000 || c.FrameShift != 80 {
+		t.Errorf("SampleRate and FrameShift are %d and %d, want 16000 and 80.",
+			c.SampleRate, c.FrameShift)
+	}
+
+	expectedVoiced := []bool{false, true, true, false}
+	for i := range f0Sequence {
+		if c.Voiced[i] != expectedVoiced[i] {
+			t.Errorf("Voiced[%d] is %v, want %v.", i, c.Voiced[i], expectedVoiced[i])
+		}
+		if c.Times[i] != float64(i)*0.005 {
+			t.Errorf("Times[%d] is %f, want %f.", i, c.Times[i], float64(i)*0.005)
+		}
+	}
+}
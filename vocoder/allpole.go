@@ -0,0 +1,102 @@
+package vocoder
+
+// AllPoleFilter represents an all-pole filter 1/A(z), where
+// A(z) = 1 + a(1)z^-1 + ... + a(p)z^-p, which is the LPC synthesis filter
+// (poledf in SPTK).
+type AllPoleFilter struct {
+	Order int // Order of LPC
+
+	delay []float64
+	delayLines
+}
+
+// NewAllPoleFilter returns its instance and error given the order of LPC.
+func NewAllPoleFilter(order int) (*AllPoleFilter, error) {
+	if order < 0 {
+		return nil, ErrInvalidOrder
+	}
+	f := &AllPoleFilter{
+		Order: order,
+		delay: make([]float64, order),
+	}
+	f.delayLines = newDelayLines(f.delay)
+	return f, nil
+}
+
+// Filter returns filtered sample given the input sample and LPC
+// (K, a(1), ..., a(p)). The gain K is not applied.
+func (f *AllPoleFilter) Filter(sample float64, coef []float64) float64 {
+	result := sample
+	for i := 1; i < len(coef); i++ {
+		result -= coef[i] * f.delay[i-1]
+	}
+
+	// t <- t+1 in time
+	for i := len(f.delay) - 1; i > 0; i-- {
+		f.delay[i] = f.delay[i-1]
+	}
+	if len(f.delay) > 0 {
+		f.delay[0] = result
+	}
+
+	return result
+}
+
+// Clone returns a deep copy of the filter.
+func (f *AllPoleFilter) Clone() *AllPoleFilter {
+	clone := *f
+	clone.delay = cloneDelay(f.delay)
+	clone.delayLines = newDelayLines(clone.delay)
+	return &clone
+}
+
+// AllZeroFilter represents an all-zero (FIR) filter
+// B(z) = 1 + b(1)z^-1 + ... + b(m)z^-m (zerodf in SPTK). Given LPC, it is
+// the inverse filter A(z) that yields the prediction error.
+type AllZeroFilter struct {
+	Order int // Order of the filter
+
+	delay []float64
+	delayLines
+}
+
+// NewAllZeroFilter returns its instance and error given the order of the
+// filter.
+func NewAllZeroFilter(order int) (*AllZeroFilter, error) {
+	if order < 0 {
+		return nil, ErrInvalidOrder
+	}
+	f := &AllZeroFilter{
+		Order: order,
+		delay: make([]float64, order),
+	}
+	f.delayLines = newDelayLines(f.delay)
+	return f, nil
+}
+
+// Filter returns filtered sample given the input sample and coefficients
+// (K, b(1), ..., b(m)). The gain K is not applied.
+func (f *AllZeroFilter) Filter(sample float64, coef []float64) float64 {
+	result := sample
+	for i := 1; i < len(coef); i++ {
+		result += coef[i] * f.delay[i-1]
+	}
+
+	// t <- t+1 in time
+	for i := len(f.delay) - 1; i > 0; i-- {
+		f.delay[i] = f.delay[i-1]
+	}
+	if len(f.delay) > 0 {
+		f.delay[0] = sample
+	}
+
+	return result
+}
+
+// Clone returns a deep copy of the filter.
+func (f *AllZeroFilter) Clone() *AllZeroFilter {
+	clone := *f
+	clone.delay = cloneDelay(f.delay)
+	clone.delayLines = newDelayLines(clone.delay)
+	return &clone
+}
@@ -0,0 +1,65 @@
+package vocoder
+
+import (
+	"errors"
+)
+
+// FilterState represents a snapshot of the delay lines of a filter, which
+// can be restored to a filter of the same configuration.
+type FilterState [][]float64
+
+// ErrFilterStateMismatch is returned when a filter state does not match the
+// configuration of a filter.
+var ErrFilterStateMismatch = errors.New("vocoder: filter state does not match the filter")
+
+// delayLines refers to the delay lines of a filter, including those of its
+// component filters, and provides Reset, Snapshot and Restore to filters
+// that embed it. Delay lines must be updated in place.
+type delayLines struct {
+	lines [][]float64
+}
+
+func newDelayLines(lines ...[]float64) delayLines {
+	return delayLines{lines: lines}
+}
+
+// Reset clears the delay lines of the filter.
+func (d *delayLines) Reset() {
+	for _, line := range d.lines {
+		for i := range line {
+			line[i] = 0.0
+		}
+	}
+}
+
+// Snapshot returns the current state of the filter.
+func (d *delayLines) Snapshot() FilterState {
+	state := make(FilterState, len(d.lines))
+	for i, line := range d.lines {
+		state[i] = cloneDelay(line)
+	}
+	return state
+}
+
+// Restore restores a state returned by Snapshot, which must be taken from
+// a filter of the same configuration.
+func (d *delayLines) Restore(state FilterState) error {
+	if len(state) != len(d.lines) {
+		return ErrFilterStateMismatch
+	}
+	for i, line := range d.lines {
+		if len(state[i]) != len(line) {
+			return ErrFilterStateMismatch
+		}
+	}
+	for i, line := range d.lines {
+		copy(line, state[i])
+	}
+	return nil
+}
+
+func cloneDelay(delay []float64) []float64 {
+	clone := make([]float64, len(delay))
+	copy(clone, delay)
+	return clone
+}
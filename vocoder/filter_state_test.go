@@ -0,0 +1,241 @@
+package vocoder
+
+import (
+	"github.com/r9y9/gossp/excite"
+	"github.com/r9y9/gossp/lpc"
+	"math"
+	"testing"
+)
+
+// sampleFilter is implemented by MLSAFilter and MGLSAFilter.
+type sampleFilter interface {
+	Filter(sample float64, filterCoef []float64) float64
+}
+
+func filterImpulses(f sampleFilter, coef []float64, length int) []float64 {
+	y := make([]float64, length)
+	for i := range y {
+		x := 0.0
+		if i%50 == 0 {
+			x = 1.0
+		}
+		y[i] = f.Filter(x, coef)
+	}
+	return y
+}
+
+func equalSignals(t *testing.T, x, y []float64, label string) {
+	for i := range x {
+		if math.Abs(x[i]-y[i]) > 1.0e-12 {
+			t.Errorf("%s: sample %d = %f, want %f.", label, i, y[i], x[i])
+			return
+		}
+	}
+}
+
+func TestMLSAFilterState(t *testing.T) {
+	order, alpha := 24, 0.41
+	_, params := createTestF0AndParams(1, order)
+	coef := MCep2MLSAFilterCoef(params[0], alpha)
+
+	f, err := NewMLSAFilter(order, alpha, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh := filterImpulses(f, coef, 200)
+
+	state := f.Snapshot()
+	clone := f.Clone()
+	continued := filterImpulses(f, coef, 200)
+	equalSignals(t, continued, filterImpulses(clone, coef, 200), "Clone")
+
+	if err := f.Restore(state); err != nil {
+		t.Fatalf("Restore returns %v, want nil.", err)
+	}
+	equalSignals(t, continued, filterImpulses(f, coef, 200), "Restore")
+
+	f.Reset()
+	equalSignals(t, fresh, filterImpulses(f, coef, 200), "Reset")
+
+	other, err := NewMLSAFilter(order+1, alpha, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Restore(state); err != ErrFilterStateMismatch {
+		t.Errorf("Restore returns %v, want %v.", err, ErrFilterStateMismatch)
+	}
+}
+
+func TestMGLSAFilterState(t *testing.T) {
+	order, alpha, numStage := 24, 0.41, 3
+	_, params := createTestF0AndParams(1, order)
+	coef := MGCep2MGLSAFilterCoef(params[0], alpha, -1.0/float64(numStage))
+
+	f, err := NewMGLSAFilter(order, alpha, numStage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh := filterImpulses(f, coef, 200)
+
+	state := f.Snapshot()
+	clone := f.Clone()
+	continued := filterImpulses(f, coef, 200)
+	equalSignals(t, continued, filterImpulses(clone, coef, 200), "Clone")
+
+	if err := f.Restore(state); err != nil {
+		t.Fatalf("Restore returns %v, want nil.", err)
+	}
+	equalSignals(t, continued, filterImpulses(f, coef, 200), "Restore")
+
+	f.Reset()
+	equalSignals(t, fresh, filterImpulses(f, coef, 200), "Reset")
+
+	other, err := NewMGLSAFilter(order, alpha, numStage+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Restore(state); err != ErrFilterStateMismatch {
+		t.Errorf("Restore returns %v, want %v.", err, ErrFilterStateMismatch)
+	}
+}
+
+func TestSynthesisReusesSynthesizer(t *testing.T) {
+	sampleRate, frameShift, order, alpha := 16000, 80, 24, 0.41
+	f0Sequence, params := createTestF0AndParams(30, order)
+	ex := excite.NewPulseExcite(sampleRate, frameShift).Generate(f0Sequence)
+
+	mlsa, err := NewMLSASpeechSynthesizer(order, alpha, 5, frameShift)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := mlsa.Synthesis(ex, params)
+	equalSignals(t, first, mlsa.Synthesis(ex, params), "MLSA")
+	equalSignals(t, first, mlsa.Clone().Synthesis(ex, params), "MLSA clone")
+
+	mglsa, err := NewMGLSASpeechSynthesizer(order, alpha, 3, frameShift)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first = mglsa.Synthesis(ex, params)
+	equalSignals(t, first, mglsa.Synthesis(ex, params), "MGLSA")
+	equalSignals(t, first, mglsa.Clone().Synthesis(ex, params), "MGLSA clone")
+
+	s, err := NewMLSAStreamingSynthesizer(sampleRate, frameShift, order, alpha, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streamed [][]float64
+	for n := 0; n < 2; n++ {
+		// Noise of unvoiced frames is reproduced by the seed
+		s.Reset()
+		s.Source.Seed(excite.DefaultSeed)
+		var y []float64
+		for i := range f0Sequence {
+			y = append(y, s.Push(f0Sequence[i], params[i])...)
+		}
+		streamed = append(streamed, y)
+	}
+	equalSignals(t, streamed[0], streamed[1], "Streaming")
+}
+
+// statefulFilter is implemented by all filters in this package.
+type statefulFilter interface {
+	sampleFilter
+	Reset()
+	Snapshot() FilterState
+	Restore(state FilterState) error
+}
+
+func TestFilterStates(t *testing.T) {
+	order, alpha := 8, 0.41
+	_, params := createTestF0AndParams(1, order)
+	mlsaCoef := MCep2MLSAFilterCoef(params[0], alpha)
+	mglsaCoef := MGCep2MGLSAFilterCoef(params[0], alpha, -1.0)
+	lpcCoef := lpc.LSP2LPC(testLSP)
+	parcor, err := lpc.LPC2PARCOR(lpcCoef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mlsaBase, _ := NewMLSABaseFilter(order+1, alpha)
+	mglsaBase, _ := NewMGLSABaseFilter(order, alpha)
+	allPole, _ := NewAllPoleFilter(order)
+	allZero, _ := NewAllZeroFilter(order)
+	lsp, _ := NewLSPFilter(order)
+	lattice, _ := NewLatticeSynthesisFilter(order)
+	inverseLattice, _ := NewLatticeAnalysisFilter(order)
+
+	for _, test := range []struct {
+		name   string
+		filter statefulFilter
+		clone  func() sampleFilter
+		coef   []float64
+	}{
+		{"MLSABaseFilter", mlsaBase,
+			func() sampleFilter { return mlsaBase.Clone() }, mlsaCoef},
+		{"MGLSABaseFilter", mglsaBase,
+			func() sampleFilter { return mglsaBase.Clone() }, mglsaCoef},
+		{"AllPoleFilter", allPole,
+			func() sampleFilter { return allPole.Clone() }, lpcCoef},
+		{"AllZeroFilter", allZero,
+			func() sampleFilter { return allZero.Clone() }, lpcCoef},
+		{"LSPFilter", lsp,
+			func() sampleFilter { return lsp.Clone() }, testLSP},
+		{"LatticeSynthesisFilter", lattice,
+			func() sampleFilter { return lattice.Clone() }, parcor},
+		{"LatticeAnalysisFilter", inverseLattice,
+			func() sampleFilter { return inverseLattice.Clone() }, parcor},
+	} {
+		f := test.filter
+		fresh := filterImpulses(f, test.coef, 120)
+
+		state := f.Snapshot()
+		clone := test.clone()
+		continued := filterImpulses(f, test.coef, 120)
+		equalSignals(t, continued, filterImpulses(clone, test.coef, 120),
+			test.name+" clone")
+
+		if err := f.Restore(state); err != nil {
+			t.Fatalf("%s: Restore returns %v, want nil.", test.name, err)
+		}
+		equalSignals(t, continued, filterImpulses(f, test.coef, 120),
+			test.name+" restore")
+
+		f.Reset()
+		equalSignals(t, fresh, filterImpulses(f, test.coef, 120),
+			test.name+" reset")
+
+		if err := f.Restore(FilterState{}); err != ErrFilterStateMismatch {
+			t.Errorf("%s: Restore returns %v, want %v.", test.name, err,
+				ErrFilterStateMismatch)
+		}
+	}
+}
+
+func TestSynthesizerSnapshot(t *testing.T) {
+	sampleRate, frameShift, order, alpha := 16000, 80, 24, 0.41
+	f0Sequence, params := createTestF0AndParams(20, order)
+	ex := excite.NewPulseExcite(sampleRate, frameShift).Generate(f0Sequence)
+
+	s, err := NewMLSASpeechSynthesizer(order, alpha, 5, frameShift)
+	if err != nil {
+		t.Fatal(err)
+	}
+	synthesize := func(start int) []float64 {
+		var y []float64
+		for i := start; i < len(params); i++ {
+			y = append(y, s.SynthesisOneFrame(
+				ex[i*frameShift:(i+1)*frameShift], params[i-1], params[i])...)
+		}
+		return y
+	}
+
+	s.Reset()
+	synthesize(1)
+	state := s.Snapshot()
+	continued := synthesize(10)
+	if err := s.Restore(state); err != nil {
+		t.Fatalf("Restore returns %v, want nil.", err)
+	}
+	equalSignals(t, continued, synthesize(10), "MLSA synthesizer")
+}
@@ -0,0 +1,103 @@
+package vocoder
+
+// LatticeSynthesisFilter represents an all-pole lattice filter given by
+// PARCOR coefficients (ltcdf in SPTK), which is equivalent to the
+// all-pole filter of the corresponding LPC and stable as long as the
+// absolute values of PARCOR coefficients are less than 1.
+type LatticeSynthesisFilter struct {
+	Order int // Order of PARCOR
+
+	// delay[m] is the backward prediction error of the m-th stage
+	delay []float64
+	delayLines
+}
+
+// NewLatticeSynthesisFilter returns its instance and error given the order
+// of PARCOR.
+func NewLatticeSynthesisFilter(order int) (*LatticeSynthesisFilter, error) {
+	if order < 0 {
+		return nil, ErrInvalidOrder
+	}
+	f := &LatticeSynthesisFilter{
+		Order: order,
+		delay: make([]float64, order),
+	}
+	f.delayLines = newDelayLines(f.delay)
+	return f, nil
+}
+
+// Filter returns filtered sample given the input sample (the forward
+// prediction error) and PARCOR coefficients (K, k(1), ..., k(p)). The gain
+// K is not applied.
+func (f *LatticeSynthesisFilter) Filter(sample float64,
+	parcor []float64) float64 {
+	p := len(parcor) - 1
+	forward := sample
+	for m := p; m >= 1; m-- {
+		forward -= parcor[m] * f.delay[m-1]
+		if m < p {
+			f.delay[m] = f.delay[m-1] + parcor[m]*forward
+		}
+	}
+	if p > 0 {
+		f.delay[0] = forward
+	}
+
+	return forward
+}
+
+// Clone returns a deep copy of the filter.
+func (f *LatticeSynthesisFilter) Clone() *LatticeSynthesisFilter {
+	clone := *f
+	clone.delay = cloneDelay(f.delay)
+	clone.delayLines = newDelayLines(clone.delay)
+	return &clone
+}
+
+// LatticeAnalysisFilter represents an all-zero lattice filter given by
+// PARCOR coefficients, which is the inverse of LatticeSynthesisFilter and
+// yields the forward prediction error.
+type LatticeAnalysisFilter struct {
+	Order int // Order of PARCOR
+
+	// delay[m] is the backward prediction error of the m-th stage
+	delay []float64
+	delayLines
+}
+
+// NewLatticeAnalysisFilter returns its instance and error given the order
+// of PARCOR.
+func NewLatticeAnalysisFilter(order int) (*LatticeAnalysisFilter, error) {
+	if order < 0 {
+		return nil, ErrInvalidOrder
+	}
+	f := &LatticeAnalysisFilter{
+		Order: order,
+		delay: make([]float64, order),
+	}
+	f.delayLines = newDelayLines(f.delay)
+	return f, nil
+}
+
+// Filter returns the forward prediction error given the input sample and
+// PARCOR coefficients (K, k(1), ..., k(p)). The gain K is not applied.
+func (f *LatticeAnalysisFilter) Filter(sample float64,
+	parcor []float64) float64 {
+	forward, backward := sample, sample
+	for m := 1; m < len(parcor); m++ {
+		previous := f.delay[m-1]
+		f.delay[m-1] = backward
+		forward, backward = forward+parcor[m]*previous,
+			previous+parcor[m]*forward
+	}
+
+	return forward
+}
+
+// Clone returns a deep copy of the filter.
+func (f *LatticeAnalysisFilter) Clone() *LatticeAnalysisFilter {
+	clone := *f
+	clone.delay = cloneDelay(f.delay)
+	clone.delayLines = newDelayLines(clone.delay)
+	return &clone
+}
@@ -0,0 +1,188 @@
+package vocoder
+
+// AllPoleSpeechSynthesizer represents a speech synthesizer based on the
+// all-pole filter given by LPC.
+type AllPoleSpeechSynthesizer struct {
+	FrameShift int
+	coreFilter *AllPoleFilter // used in sample by sample waveform generation
+}
+
+// NewAllPoleSpeechSynthesizer returns its instance and error given
+// parameters.
+func NewAllPoleSpeechSynthesizer(order int,
+	frameShift int) (*AllPoleSpeechSynthesizer, error) {
+	coreFilter, err := NewAllPoleFilter(order)
+	if err != nil {
+		return nil, err
+	}
+	return &AllPoleSpeechSynthesizer{
+		FrameShift: frameShift,
+		coreFilter: coreFilter,
+	}, nil
+}
+
+// Reset clears the state of the filter, which is called at the beginning
+// of Synthesis.
+func (s *AllPoleSpeechSynthesizer) Reset() {
+	s.coreFilter.Reset()
+}
+
+// Snapshot returns the current state of the filter, which can be used to
+// resume synthesis from the current frame.
+func (s *AllPoleSpeechSynthesizer) Snapshot() FilterState {
+	return s.coreFilter.Snapshot()
+}
+
+// Restore restores a state returned by Snapshot.
+func (s *AllPoleSpeechSynthesizer) Restore(state FilterState) error {
+	return s.coreFilter.Restore(state)
+}
+
+// Clone returns a deep copy of the synthesizer, which can be used in
+// parallel with the original.
+func (s *AllPoleSpeechSynthesizer) Clone() *AllPoleSpeechSynthesizer {
+	clone := *s
+	clone.coreFilter = s.coreFilter.Clone()
+	return &clone
+}
+
+// Synthesis synthesizes a speech signal from an excitation signal and
+// corresponding LPC sequence. The state of the filter is reset before
+// synthesis.
+func (s *AllPoleSpeechSynthesizer) Synthesis(excite []float64,
+	lpcSequence [][]float64) []float64 {
+	return synthesize(s, s.FrameShift, excite, lpcSequence)
+}
+
+// SynthesisOneFrame synthesizes a part of speech signal from an excitation
+// signal and succesive two LPC (K, a(1), ..., a(p)), which are linearly
+// interpolated. Note that interpolated LPC may give an unstable filter.
+func (s *AllPoleSpeechSynthesizer) SynthesisOneFrame(excite []float64,
+	previousLPC, currentLPC []float64) []float64 {
+	return synthesizeFrame(excite, previousLPC, currentLPC, linearGain,
+		s.coreFilter.Filter)
+}
+
+// AllZeroSpeechSynthesizer represents a synthesizer based on the all-zero
+// filter, which can be used to compute the prediction error from LPC.
+type AllZeroSpeechSynthesizer struct {
+	FrameShift int
+	coreFilter *AllZeroFilter // used in sample by sample waveform generation
+}
+
+// NewAllZeroSpeechSynthesizer returns its instance and error given
+// parameters.
+func NewAllZeroSpeechSynthesizer(order int,
+	frameShift int) (*AllZeroSpeechSynthesizer, error) {
+	coreFilter, err := NewAllZeroFilter(order)
+	if err != nil {
+		return nil, err
+	}
+	return &AllZeroSpeechSynthesizer{
+		FrameShift: frameShift,
+		coreFilter: coreFilter,
+	}, nil
+}
+
+// Reset clears the state of the filter, which is called at the beginning
+// of Synthesis.
+func (s *AllZeroSpeechSynthesizer) Reset() {
+	s.coreFilter.Reset()
+}
+
+// Snapshot returns the current state of the filter, which can be used to
+// resume synthesis from the current frame.
+func (s *AllZeroSpeechSynthesizer) Snapshot() FilterState {
+	return s.coreFilter.Snapshot()
+}
+
+// Restore restores a state returned by Snapshot.
+func (s *AllZeroSpeechSynthesizer) Restore(state FilterState) error {
+	return s.coreFilter.Restore(state)
+}
+
+// Clone returns a deep copy of the synthesizer, which can be used in
+// parallel with the original.
+func (s *AllZeroSpeechSynthesizer) Clone() *AllZeroSpeechSynthesizer {
+	clone := *s
+	clone.coreFilter = s.coreFilter.Clone()
+	return &clone
+}
+
+// Synthesis filters an input signal with corresponding coefficient
+// sequence. The state of the filter is reset before synthesis.
+func (s *AllZeroSpeechSynthesizer) Synthesis(excite []float64,
+	coefSequence [][]float64) []float64 {
+	return synthesize(s, s.FrameShift, excite, coefSequence)
+}
+
+// SynthesisOneFrame filters a part of an input signal with succesive two
+// coefficients (K, b(1), ..., b(m)), which are linearly interpolated.
+func (s *AllZeroSpeechSynthesizer) SynthesisOneFrame(excite []float64,
+	previousCoef, currentCoef []float64) []float64 {
+	return synthesizeFrame(excite, previousCoef, currentCoef, linearGain,
+		s.coreFilter.Filter)
+}
+
+// LatticeSpeechSynthesizer represents a speech synthesizer based on the
+// lattice filter given by PARCOR coefficients.
+type LatticeSpeechSynthesizer struct {
+	FrameShift int
+	coreFilter *LatticeSynthesisFilter // used in sample by sample waveform generation
+}
+
+// NewLatticeSpeechSynthesizer returns its instance and error given
+// parameters.
+func NewLatticeSpeechSynthesizer(order int,
+	frameShift int) (*LatticeSpeechSynthesizer, error) {
+	coreFilter, err := NewLatticeSynthesisFilter(order)
+	if err != nil {
+		return nil, err
+	}
+	return &LatticeSpeechSynthesizer{
+		FrameShift: frameShift,
+		coreFilter: coreFilter,
+	}, nil
+}
+
+// Reset clears the state of the filter, which is called at the beginning
+// of Synthesis.
+func (s *LatticeSpeechSynthesizer) Reset() {
+	s.coreFilter.Reset()
+}
+
+// Snapshot returns the current state of the filter, which can be used to
+// resume synthesis from the current frame.
+func (s *LatticeSpeechSynthesizer) Snapshot() FilterState {
+	return s.coreFilter.Snapshot()
+}
+
+// Restore restores a state returned by Snapshot.
+func (s *LatticeSpeechSynthesizer) Restore(state FilterState) error {
+	return s.coreFilter.Restore(state)
+}
+
+// Clone returns a deep copy of the synthesizer, which can be used in
+// parallel with the original.
+func (s *LatticeSpeechSynthesizer) Clone() *LatticeSpeechSynthesizer {
+	clone := *s
+	clone.coreFilter = s.coreFilter.Clone()
+	return &clone
+}
+
+// Synthesis synthesizes a speech signal from an excitation signal and
+// corresponding PARCOR sequence. The state of the filter is reset before
+// synthesis.
+func (s *LatticeSpeechSynthesizer) Synthesis(excite []float64,
+	parcorSequence [][]float64) []float64 {
+	return synthesize(s, s.FrameShift, excite, parcorSequence)
+}
+
+// SynthesisOneFrame synthesizes a part of speech signal from an excitation
+// signal and succesive two PARCOR (K, k(1), ..., k(p)), which are linearly
+// interpolated. Interpolated PARCOR keeps the filter stable.
+func (s *LatticeSpeechSynthesizer) SynthesisOneFrame(excite []float64,
+	previousPARCOR, currentPARCOR []float64) []float64 {
+	return synthesizeFrame(excite, previousPARCOR, currentPARCOR, linearGain,
+		s.coreFilter.Filter)
+}
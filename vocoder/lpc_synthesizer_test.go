@@ -0,0 +1,88 @@
+package vocoder
+
+import (
+	"github.com/r9y9/gossp/excite"
+	"github.com/r9y9/gossp/lpc"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func createNoise(length int) []float64 {
+	r := rand.New(rand.NewSource(1))
+	x := make([]float64, length)
+	for i := range x {
+		x[i] = r.NormFloat64()
+	}
+	return x
+}
+
+func TestAllPoleAndLatticeFilters(t *testing.T) {
+	coef := lpc.LSP2LPC(testLSP)
+	parcor, err := lpc.LPC2PARCOR(coef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := len(coef) - 1
+
+	allPole, _ := NewAllPoleFilter(order)
+	allZero, _ := NewAllZeroFilter(order)
+	lattice, _ := NewLatticeSynthesisFilter(order)
+	inverseLattice, _ := NewLatticeAnalysisFilter(order)
+
+	for n, x := range createNoise(500) {
+		y1 := allPole.Filter(x, coef)
+		y2 := lattice.Filter(x, parcor)
+		if math.Abs(y1-y2) > 1.0e-10 {
+			t.Fatalf("Sample %d of lattice filter = %f, want %f.", n, y2, y1)
+		}
+
+		// Inverse filters yield the input
+		if e := allZero.Filter(y1, coef); math.Abs(e-x) > 1.0e-10 {
+			t.Fatalf("Sample %d of all-zero filter = %f, want %f.", n, e, x)
+		}
+		if e := inverseLattice.Filter(y2, parcor); math.Abs(e-x) > 1.0e-10 {
+			t.Fatalf("Sample %d of lattice analysis filter = %f, want %f.",
+				n, e, x)
+		}
+	}
+}
+
+func TestSynthesizersOnIdenticalExcitation(t *testing.T) {
+	sampleRate, frameShift, numFrames := 16000, 80, 20
+	f0Sequence, _ := createTestF0AndParams(numFrames, 0)
+	ex := excite.NewPulseExcite(sampleRate, frameShift).Generate(f0Sequence)
+
+	coef := lpc.LSP2LPC(testLSP)
+	parcor, _ := lpc.LPC2PARCOR(coef)
+	constant := func(param []float64) [][]float64 {
+		sequence := make([][]float64, numFrames)
+		for i := range sequence {
+			sequence[i] = param
+		}
+		return sequence
+	}
+	order := len(coef) - 1
+
+	allPole, _ := NewAllPoleSpeechSynthesizer(order, frameShift)
+	lattice, _ := NewLatticeSpeechSynthesizer(order, frameShift)
+	lsp, _ := NewLSPSpeechSynthesizer(order, frameShift)
+	allZero, _ := NewAllZeroSpeechSynthesizer(order, frameShift)
+
+	expected := allPole.Synthesis(ex, constant(coef))
+	equalSignals(t, expected, lattice.Synthesis(ex, constant(parcor)),
+		"Lattice")
+	equalSignals(t, expected, lsp.Synthesis(ex, constant(testLSP)), "LSP")
+
+	// The inverse filter with the inverse gain yields the excitation
+	inverse := make([]float64, len(coef))
+	copy(inverse, coef)
+	inverse[0] = 1.0 / coef[0]
+	equalSignals(t, ex, allZero.Synthesis(expected, constant(inverse)),
+		"All-zero")
+
+	if _, err := NewLatticeSpeechSynthesizer(-1, frameShift); err != ErrInvalidOrder {
+		t.Errorf("NewLatticeSpeechSynthesizer returns %v, want %v.",
+			err, ErrInvalidOrder)
+	}
+}
@@ -0,0 +1,108 @@
+package vocoder
+
+import (
+	"math"
+)
+
+// LSPFilter represents an all-pole synthesis filter 1/A(z) whose
+// coefficients are given by line spectral pairs (lspdf in SPTK).
+//
+// A(z) is decomposed into the sum and difference polynomials
+// A(z) = (P(z) + Q(z)) / 2, each of which is a cascade of second order
+// sections 1 - 2cos(w(i))z^-1 + z^-2 (and trivial sections at z = -1 or
+// z = 1). Since F(z) - 1 of a section has delay terms only, the feedback
+// (A(z) - 1)y is computed from the delays of each section, and LSP is used
+// directly without conversion to LPC.
+type LSPFilter struct {
+	Order int // Order of LSP
+
+	numSumSections int
+	sections       []float64 // b(1), b(2) of each section
+	delay          []float64 // past two inputs of each section
+	delayLines
+}
+
+// NewLSPFilter returns its instance and error given the order of LSP.
+func NewLSPFilter(order int) (*LSPFilter, error) {
+	if order < 0 {
+		return nil, ErrInvalidOrder
+	}
+
+	// Odd-numbered and even-numbered frequencies belong to the sum and
+	// difference polynomials respectively, and the difference polynomial
+	// always has a trivial section.
+	numSumSections, numDifferenceSections := (order+1)/2, order/2+1
+	if order%2 == 0 {
+		numSumSections++
+	}
+	numSections := numSumSections + numDifferenceSections
+
+	f := &LSPFilter{
+		Order:          order,
+		numSumSections: numSumSections,
+		sections:       make([]float64, 2*numSections),
+		delay:          make([]float64, 2*numSections),
+	}
+	f.delayLines = newDelayLines(f.delay)
+	return f, nil
+}
+
+// Filter returns filtered sample given the input sample and LSP
+// (K, w(1), ..., w(p)). The gain K is not applied.
+func (f *LSPFilter) Filter(sample float64, lsp []float64) float64 {
+	f.setSections(lsp)
+
+	feedback := 0.0
+	for i := 0; i < len(f.delay); i += 2 {
+		feedback += f.sections[i]*f.delay[i] + f.sections[i+1]*f.delay[i+1]
+	}
+	result := sample - 0.5*feedback
+
+	// t <- t+1 in time through both cascades
+	input := result
+	for i := 0; i < len(f.delay); i += 2 {
+		if i == 2*f.numSumSections {
+			input = result
+		}
+		output := input + f.sections[i]*f.delay[i] +
+			f.sections[i+1]*f.delay[i+1]
+		f.delay[i+1] = f.delay[i]
+		f.delay[i] = input
+		input = output
+	}
+
+	return result
+}
+
+// setSections sets coefficients of second order sections of the sum and
+// difference polynomials given LSP.
+func (f *LSPFilter) setSections(lsp []float64) {
+	sum := f.sections[:2*f.numSumSections]
+	difference := f.sections[2*f.numSumSections:]
+	for i := 1; i <= f.Order; i++ {
+		b1 := -2.0 * math.Cos(lsp[i])
+		if i%2 == 1 {
+			sum[i-1], sum[i] = b1, 1.0
+		} else {
+			difference[i-2], difference[i-1] = b1, 1.0
+		}
+	}
+
+	// Trivial sections at z = -1 and z = 1
+	last := len(difference) - 2
+	if f.Order%2 == 0 {
+		sum[len(sum)-2], sum[len(sum)-1] = 1.0, 0.0
+		difference[last], difference[last+1] = -1.0, 0.0
+	} else {
+		difference[last], difference[last+1] = 0.0, -1.0
+	}
+}
+
+// Clone returns a deep copy of the filter.
+func (f *LSPFilter) Clone() *LSPFilter {
+	clone := *f
+	clone.sections = make([]float64, len(f.sections))
+	clone.delay = cloneDelay(f.delay)
+	clone.delayLines = newDelayLines(clone.delay)
+	return &clone
+}
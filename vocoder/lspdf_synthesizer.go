@@ -0,0 +1,67 @@
+package vocoder
+
+// LSPSpeechSynthesizer represents a speech synthesizer based on the LSP
+// filter.
+type LSPSpeechSynthesizer struct {
+	FrameShift int
+	coreFilter *LSPFilter // used in sample by sample waveform generation
+}
+
+// NewLSPSpeechSynthesizer returns its instance and error given parameters.
+func NewLSPSpeechSynthesizer(order int,
+	frameShift int) (*LSPSpeechSynthesizer, error) {
+	coreFilter, err := NewLSPFilter(order)
+	if err != nil {
+		return nil, err
+	}
+
+	synthesizer := &LSPSpeechSynthesizer{
+		FrameShift: frameShift,
+		coreFilter: coreFilter,
+	}
+
+	return synthesizer, nil
+}
+
+// Reset clears the state of the filter, which is called at the beginning
+// of Synthesis.
+func (s *LSPSpeechSynthesizer) Reset() {
+	s.coreFilter.Reset()
+}
+
+// Snapshot returns the current state of the filter, which can be used to
+// resume synthesis from the current frame.
+func (s *LSPSpeechSynthesizer) Snapshot() FilterState {
+	return s.coreFilter.Snapshot()
+}
+
+// Restore restores a state returned by Snapshot.
+func (s *LSPSpeechSynthesizer) Restore(state FilterState) error {
+	return s.coreFilter.Restore(state)
+}
+
+// Clone returns a deep copy of the synthesizer, which can be used in
+// parallel with the original.
+func (s *LSPSpeechSynthesizer) Clone() *LSPSpeechSynthesizer {
+	clone := *s
+	clone.coreFilter = s.coreFilter.Clone()
+	return &clone
+}
+
+// Synthesis synthesizes a speech signal from an excitation signal and
+// corresponding LSP sequence. The state of the filter is reset before
+// synthesis.
+func (s *LSPSpeechSynthesizer) Synthesis(excite []float64,
+	lspSequence [][]float64) []float64 {
+	return synthesize(s, s.FrameShift, excite, lspSequence)
+}
+
+// SynthesisOneFrame synthesizes a part of speech signal from an excitation
+// signal and succesive two LSP (K, w(1), ..., w(p)). The gain K and
+// frequencies between two succesive LSP are linearly interpolated, which
+// keeps the filter stable as long as frequencies are in ascending order.
+func (s *LSPSpeechSynthesizer) SynthesisOneFrame(excite []float64,
+	previousLSP, currentLSP []float64) []float64 {
+	return synthesizeFrame(excite, previousLSP, currentLSP, linearGain,
+		s.coreFilter.Filter)
+}
@@ -0,0 +1,76 @@
+package vocoder
+
+import (
+	"github.com/r9y9/gossp/excite"
+	"github.com/r9y9/gossp/lpc"
+	"math"
+	"testing"
+)
+
+var testLSP = []float64{0.5, 0.3, 0.5, 1.0, 1.3, 1.9, 2.2, 2.6, 2.9}
+
+func TestLSPFilter(t *testing.T) {
+	// Even and odd orders
+	for _, lsp := range [][]float64{testLSP, testLSP[:len(testLSP)-1]} {
+		coef := lpc.LSP2LPC(lsp)
+		order := len(lsp) - 1
+		f, err := NewLSPFilter(order)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Impulse response of 1/A(z)
+		length := 200
+		expected := make([]float64, length)
+		for n := range expected {
+			if n == 0 {
+				expected[n] = 1.0
+			}
+			for i := 1; i <= order && n-i >= 0; i++ {
+				expected[n] -= coef[i] * expected[n-i]
+			}
+		}
+		for n := 0; n < length; n++ {
+			x := 0.0
+			if n == 0 {
+				x = 1.0
+			}
+			if y := f.Filter(x, lsp); math.Abs(y-expected[n]) > 1.0e-10 {
+				t.Errorf("Impulse response[%d] of order %d = %f, want %f.",
+					n, order, y, expected[n])
+			}
+		}
+	}
+}
+
+func TestLSPSynthesis(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	f0Sequence, _ := createTestF0AndParams(30, 0)
+	lspSequence := make([][]float64, len(f0Sequence))
+	for i := range lspSequence {
+		lspSequence[i] = make([]float64, len(testLSP))
+		copy(lspSequence[i], testLSP)
+		// Shift frequencies keeping the order
+		for j := 1; j < len(testLSP); j++ {
+			lspSequence[i][j] += 0.05 * math.Sin(float64(i)/5.0)
+		}
+	}
+	ex := excite.NewPulseExcite(sampleRate, frameShift).Generate(f0Sequence)
+
+	synth, err := NewLSPSpeechSynthesizer(len(testLSP)-1, frameShift)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := synth.Synthesis(ex, lspSequence)
+
+	s := NewStreamingSynthesizer(
+		excite.NewPulseExcite(sampleRate, frameShift),
+		synth.Clone(), frameShift)
+	s.Reset()
+	testStreamingSynthesis(t, batch, s, f0Sequence, lspSequence)
+
+	if _, err := NewLSPSpeechSynthesizer(-1, frameShift); err != ErrInvalidOrder {
+		t.Errorf("NewLSPSpeechSynthesizer returns %v, want %v.",
+			err, ErrInvalidOrder)
+	}
+}
@@ -4,6 +4,7 @@ package vocoder
 type MGLSAFilter struct {
 	cascadeFilters []*MGLSABaseFilter
 	numStage       int // -1.0/gamma
+	delayLines
 }
 
 // MGLSABaseFilter represents a base filter of MGLSAFilter.
@@ -13,15 +14,22 @@ type MGLSABaseFilter struct {
 	Alpha float64
 
 	delay []float64
+	delayLines
 }
 
-func NewMGLSABaseFilter(order int, alpha float64) *MGLSABaseFilter {
+// NewMGLSABaseFilter returns its instance and error given the order of
+// mel-generalized cepstrum and all-pass constant (alpha).
+func NewMGLSABaseFilter(order int, alpha float64) (*MGLSABaseFilter, error) {
+	if err := validateFilterParams(order, alpha); err != nil {
+		return nil, err
+	}
 	f := &MGLSABaseFilter{
 		Order: order,
 		Alpha: alpha,
 		delay: make([]float64, order+1),
 	}
-	return f
+	f.delayLines = newDelayLines(f.delay)
+	return f, nil
 }
 
 func (bf *MGLSABaseFilter) Filter(sample float64, filterCoef []float64) float64 {
@@ -43,17 +51,36 @@ func (bf *MGLSABaseFilter) Filter(sample float64, filterCoef []float64) float64
 	return result
 }
 
-func NewMGLSAFilter(order int, alpha float64, numStage int) *MGLSAFilter {
+// Clone returns a deep copy of the filter.
+func (bf *MGLSABaseFilter) Clone() *MGLSABaseFilter {
+	clone := *bf
+	clone.delay = cloneDelay(bf.delay)
+	clone.delayLines = newDelayLines(clone.delay)
+	return &clone
+}
+
+// NewMGLSAFilter returns its instance and error given the order of
+// mel-generalized cepstrum, all-pass constant (alpha) and the number of
+// stages (-1/gamma).
+func NewMGLSAFilter(order int, alpha float64,
+	numStage int) (*MGLSAFilter, error) {
+	if numStage < 1 {
+		return nil, ErrInvalidNumStage
+	}
 	f := &MGLSAFilter{
 		numStage: numStage,
 	}
 
 	for i := 0; i < numStage; i++ {
-		base := NewMGLSABaseFilter(order, alpha)
+		base, err := NewMGLSABaseFilter(order, alpha)
+		if err != nil {
+			return nil, err
+		}
 		f.cascadeFilters = append(f.cascadeFilters, base)
 	}
+	f.delayLines = f.collectDelayLines()
 
-	return f
+	return f, nil
 }
 
 func (mf *MGLSAFilter) Filter(sample float64, filterCoef []float64) float64 {
@@ -66,3 +93,25 @@ func (mf *MGLSAFilter) Filter(sample float64, filterCoef []float64) float64 {
 
 	return filterd
 }
+
+// Clone returns a deep copy of the filter.
+func (mf *MGLSAFilter) Clone() *MGLSAFilter {
+	clone := &MGLSAFilter{
+		cascadeFilters: make([]*MGLSABaseFilter, len(mf.cascadeFilters)),
+		numStage:       mf.numStage,
+	}
+	for i, bf := range mf.cascadeFilters {
+		clone.cascadeFilters[i] = bf.Clone()
+	}
+	clone.delayLines = clone.collectDelayLines()
+	return clone
+}
+
+// collectDelayLines returns the delay lines of the base filters.
+func (mf *MGLSAFilter) collectDelayLines() delayLines {
+	var lines [][]float64
+	for _, bf := range mf.cascadeFilters {
+		lines = append(lines, bf.lines...)
+	}
+	return newDelayLines(lines...)
+}
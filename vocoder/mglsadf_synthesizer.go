@@ -14,81 +14,70 @@ type MGLSASpeechSynthesizer struct {
 	coreFilter *MGLSAFilter // used in sample by sample waveform generation
 }
 
-// NewMGLSASpeechSynthesizer returns its instance given parameters.
+// NewMGLSASpeechSynthesizer returns its instance and error given
+// parameters.
 func NewMGLSASpeechSynthesizer(order int, alpha float64, numStage int,
-	frameShift int) *MGLSASpeechSynthesizer {
+	frameShift int) (*MGLSASpeechSynthesizer, error) {
+	coreFilter, err := NewMGLSAFilter(order, alpha, numStage)
+	if err != nil {
+		return nil, err
+	}
+
 	synthesizer := &MGLSASpeechSynthesizer{
 		FrameShift: frameShift,
 		Alpha:      alpha,
 		NumStage:   numStage,
 		Gamma:      -1.0 / float64(numStage),
-		coreFilter: NewMGLSAFilter(order, alpha, numStage),
+		coreFilter: coreFilter,
 	}
 
-	return synthesizer
+	return synthesizer, nil
 }
 
-// Synthesis synthesizes a speech signal from an excitation signal and
-// corresponding mel-ceptrum sequence.
-func (s *MGLSASpeechSynthesizer) Synthesis(excite []float64,
-	mgcepSequence [][]float64) []float64 {
-	// synthesized speech signal will be stored
-	synthesizedSpeech := make([]float64, len(excite))
-
-	previousMgcep := mgcepSequence[0]
-	for i, currentMgcep := range mgcepSequence {
-		if i > 0 {
-			previousMgcep = mgcepSequence[i-1]
-		}
+// Reset clears the state of the filter, which is called at the beginning
+// of Synthesis.
+func (s *MGLSASpeechSynthesizer) Reset() {
+	s.coreFilter.Reset()
+}
 
-		startIndex, endIndex := i*s.FrameShift, (i+1)*s.FrameShift
-		if endIndex >= len(excite) {
-			break
-		}
+// Snapshot returns the current state of the filter, which can be used to
+// resume synthesis from the current frame.
+func (s *MGLSASpeechSynthesizer) Snapshot() FilterState {
+	return s.coreFilter.Snapshot()
+}
 
-		// Synthesize a part of speech
-		partOfSpeech := s.SynthesisOneFrame(excite[startIndex:endIndex],
-			previousMgcep, currentMgcep)
+// Restore restores a state returned by Snapshot.
+func (s *MGLSASpeechSynthesizer) Restore(state FilterState) error {
+	return s.coreFilter.Restore(state)
+}
 
-		for j, val := range partOfSpeech {
-			synthesizedSpeech[i*s.FrameShift+j] = val
-		}
-	}
+// Clone returns a deep copy of the synthesizer, which can be used in
+// parallel with the original.
+func (s *MGLSASpeechSynthesizer) Clone() *MGLSASpeechSynthesizer {
+	clone := *s
+	clone.coreFilter = s.coreFilter.Clone()
+	return &clone
+}
 
-	return synthesizedSpeech
+// Synthesis synthesizes a speech signal from an excitation signal and
+// corresponding mel-ceptrum sequence. The state of the filter is reset
+// before synthesis.
+func (s *MGLSASpeechSynthesizer) Synthesis(excite []float64,
+	mgcepSequence [][]float64) []float64 {
+	return synthesize(s, s.FrameShift, excite, mgcepSequence)
 }
 
 // SynthesisOneFrame synthesizes a part of speech signal from an excitation signal
-// and succesive two mel-cepstrum sequence. It requires all-pass constant (alpha).
-// Mel-cepstral coefficients between two succesive mel-cepstrum are linearly
-// interpolated.
+// and succesive two mel-generalized cepstrum. It requires all-pass constant
+// (alpha) and gamma. MGLSA filter coefficients between two succesive frames
+// are linearly interpolated.
 func (s *MGLSASpeechSynthesizer) SynthesisOneFrame(excite []float64,
 	previousMgcep, currentMgcep []float64) []float64 {
-	// Convert to MGLSA filter coefficients from Mel-cepstrum
-	currentFilterCoef := MGCep2MGLSAFilterCoef(currentMgcep, s.Alpha, s.Gamma)
+	// Convert to MGLSA filter coefficients from mel-generalized cepstrum,
+	// whose first coefficient is the log gain
 	previousFilterCoef := MGCep2MGLSAFilterCoef(previousMgcep, s.Alpha, s.Gamma)
+	currentFilterCoef := MGCep2MGLSAFilterCoef(currentMgcep, s.Alpha, s.Gamma)
 
-	// Compute slope
-	slope := make([]float64, len(currentMgcep))
-	for i := 0; i < len(slope); i++ {
-		slope[i] = (currentFilterCoef[i] - previousFilterCoef[i]) /
-			float64(len(excite))
-	}
-
-	partOfSpeech := make([]float64, len(excite))
-	linearlyInterpolatedCoef := make([]float64, len(previousFilterCoef))
-	copy(linearlyInterpolatedCoef, previousFilterCoef)
-	for i := 0; i < len(excite); i++ {
-		// Multyply power coeffcient
-		scaledExcitation := excite[i] * math.Exp(linearlyInterpolatedCoef[0])
-		// Filtering
-		partOfSpeech[i] = s.coreFilter.Filter(scaledExcitation,
-			linearlyInterpolatedCoef)
-		// Linear interpolation of filter coefficients
-		for j := 0; j < len(slope); j++ {
-			linearlyInterpolatedCoef[j] += slope[j]
-		}
-	}
-
-	return partOfSpeech
+	return synthesizeFrame(excite, previousFilterCoef, currentFilterCoef,
+		math.Exp, s.coreFilter.Filter)
 }
@@ -54,7 +54,10 @@ func TestMGLSASynthesis(t *testing.T) {
 	ex = g.Generate(f0Seq)
 
 	// Waveform generation
-	synth := NewMGLSASpeechSynthesizer(order, alpha, stage, frameShift)
+	synth, err := NewMGLSASpeechSynthesizer(order, alpha, stage, frameShift)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	_ = synth.Synthesis(ex, mgc)
 	// TODO(ryuichi) valid check
@@ -37,7 +37,10 @@ func TestMGLSAConsistencyWithSPTK(t *testing.T) {
 	mgc := mgcep.MGCep(window.BlackmanNormalized(data), order, alpha, gamma)
 	filterCoef := MGCep2MGLSAFilterCoef(mgc, alpha, gamma)
 
-	mf := NewMGLSAFilter(order, alpha, stage)
+	mf, err := NewMGLSAFilter(order, alpha, stage)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// tricky allocation based on the SPTK (just for test)
 	d := make([]float64, (order+1)*stage)
@@ -0,0 +1,159 @@
+package vocoder
+
+import (
+	"github.com/mjibson/go-dsp/fft"
+	"math"
+	"math/cmplx"
+)
+
+// DefaultMLSACheckFFTLen is the FFT length to evaluate log amplitudes of
+// MLSA filters.
+const DefaultMLSACheckFFTLen = 256
+
+// maxClipIterations is the maximum number of iterations of clipping in
+// ClipLogAmplitude mode.
+const maxClipIterations = 10
+
+// StabilizationMode specifies how unstable mel-cepstrum is modified.
+type StabilizationMode int
+
+const (
+	NoStabilization   StabilizationMode = iota // Only report unstable frames
+	ScaleCoefficients                          // Scale coefficients (fast)
+	ClipLogAmplitude                           // Clip log amplitude (precise)
+)
+
+// maxLogAmplitudes are the maximum log amplitudes of MLSA filters that
+// keep the Pade approximation stable for each order.
+var maxLogAmplitudes = map[int]float64{
+	4: 4.5,
+	5: 6.2,
+	6: 7.65,
+	7: 9.13,
+}
+
+// MLSAChecker represents the stability check of the MLSA filter (mlsacheck
+// in SPTK). The MLSA filter exp(F(z)) is approximated by the Pade
+// approximation, which is stable only if the amplitude of F(z), that is the
+// log amplitude of the filter except for the gain, is less than a threshold
+// determined by the order of the Pade approximation.
+type MLSAChecker struct {
+	OrderOfPade int
+	FFTLen      int
+	Threshold   float64 // Maximum log amplitude
+	Mode        StabilizationMode
+}
+
+// NewMLSAChecker returns its instance and error with the order of the Pade
+// approximation and a stabilization mode.
+func NewMLSAChecker(orderOfPade int,
+	mode StabilizationMode) (*MLSAChecker, error) {
+	threshold, ok := maxLogAmplitudes[orderOfPade]
+	if !ok {
+		return nil, ErrInvalidOrderOfPade
+	}
+	return &MLSAChecker{
+		OrderOfPade: orderOfPade,
+		FFTLen:      DefaultMLSACheckFFTLen,
+		Threshold:   threshold,
+		Mode:        mode,
+	}, nil
+}
+
+// LogAmplitude returns the maximum log amplitude of the MLSA filter over
+// frequency, which is evaluated on the warped frequency axis where the
+// log spectrum is the Fourier transform of mel-cepstrum.
+func (c *MLSAChecker) LogAmplitude(mcep []float64) float64 {
+	max := 0.0
+	for _, val := range c.spectrum(mcep) {
+		max = math.Max(max, cmplx.Abs(val))
+	}
+	return max
+}
+
+// IsStable returns whether the MLSA filter of a given mel-cepstrum is
+// stable.
+func (c *MLSAChecker) IsStable(mcep []float64) bool {
+	return c.LogAmplitude(mcep) <= c.Threshold
+}
+
+// Check returns indices of unstable frames of a mel-cepstrum sequence.
+func (c *MLSAChecker) Check(mcepSequence [][]float64) []int {
+	var unstable []int
+	for i, mcep := range mcepSequence {
+		if !c.IsStable(mcep) {
+			unstable = append(unstable, i)
+		}
+	}
+	return unstable
+}
+
+// Stabilize returns mel-cepstrum modified by Mode so that the MLSA filter
+// becomes stable. The gain (the 0-th coefficient) is not modified.
+// ClipLogAmplitude modifies only frequencies where the log amplitude
+// exceeds the threshold, and falls back to scaling if clipping does not
+// converge.
+func (c *MLSAChecker) Stabilize(mcep []float64) []float64 {
+	stabilized := make([]float64, len(mcep))
+	copy(stabilized, mcep)
+
+	if c.IsStable(mcep) {
+		return stabilized
+	}
+
+	switch c.Mode {
+	case ScaleCoefficients:
+		c.scale(stabilized)
+	case ClipLogAmplitude:
+		// Truncation to the order after clipping may exceed the threshold
+		// again, so that clipping is repeated and followed by scaling.
+		for iter := 0; iter < maxClipIterations; iter++ {
+			spectrum := c.spectrum(stabilized)
+			for k, val := range spectrum {
+				if amplitude := cmplx.Abs(val); amplitude > c.Threshold {
+					spectrum[k] *= complex(c.Threshold/amplitude, 0.0)
+				}
+			}
+			clipped := fft.IFFT(spectrum)
+			for i := 1; i < len(stabilized); i++ {
+				stabilized[i] = real(clipped[i])
+			}
+			if c.IsStable(stabilized) {
+				break
+			}
+		}
+		c.scale(stabilized)
+	}
+
+	return stabilized
+}
+
+// scale scales coefficients except for the gain so that the maximum log
+// amplitude is equal to the threshold if it exceeds.
+func (c *MLSAChecker) scale(mcep []float64) {
+	r := c.LogAmplitude(mcep)
+	if r <= c.Threshold {
+		return
+	}
+	for i := 1; i < len(mcep); i++ {
+		mcep[i] *= c.Threshold / r
+	}
+}
+
+// StabilizeSequence applies Stabilize to each frame and returns the
+// stabilized sequence and indices of unstable frames.
+func (c *MLSAChecker) StabilizeSequence(mcepSequence [][]float64) ([][]float64,
+	[]int) {
+	stabilized := make([][]float64, len(mcepSequence))
+	for i, mcep := range mcepSequence {
+		stabilized[i] = c.Stabilize(mcep)
+	}
+	return stabilized, c.Check(mcepSequence)
+}
+
+// spectrum returns the Fourier transform of mel-cepstrum without the gain.
+func (c *MLSAChecker) spectrum(mcep []float64) []complex128 {
+	x := make([]float64, c.FFTLen)
+	copy(x[1:], mcep[1:])
+	return fft.FFTReal(x)
+}
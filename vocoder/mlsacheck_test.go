@@ -0,0 +1,59 @@
+package vocoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMLSACheck(t *testing.T) {
+	order := 24
+	_, params := createTestF0AndParams(3, order)
+
+	// The second frame is amplified to be unstable
+	mcepSequence := [][]float64{params[0], make([]float64, order+1),
+		params[2]}
+	for i := range mcepSequence[1] {
+		mcepSequence[1][i] = 20.0 * params[1][i]
+	}
+
+	for _, mode := range []StabilizationMode{NoStabilization,
+		ScaleCoefficients, ClipLogAmplitude} {
+		c, err := NewMLSAChecker(5, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unstable := c.Check(mcepSequence)
+		if len(unstable) != 1 || unstable[0] != 1 {
+			t.Fatalf("Unstable frames = %v, want [1].", unstable)
+		}
+
+		stabilized, unstable := c.StabilizeSequence(mcepSequence)
+		if len(unstable) != 1 || unstable[0] != 1 {
+			t.Errorf("Unstable frames = %v, want [1].", unstable)
+		}
+		for i, mcep := range stabilized {
+			if mcep[0] != mcepSequence[i][0] {
+				t.Errorf("Gain of frame %d = %f, want %f.",
+					i, mcep[0], mcepSequence[i][0])
+			}
+			if i == 1 && mode != NoStabilization {
+				continue
+			}
+			for j := range mcep {
+				if mcep[j] != mcepSequence[i][j] {
+					t.Errorf("Frame %d is modified in mode %d.", i, mode)
+					break
+				}
+			}
+		}
+
+		amplitude := c.LogAmplitude(stabilized[1])
+		if mode != NoStabilization && amplitude > c.Threshold+1.0e-10 {
+			t.Errorf("Log amplitude = %f, want <= %f.", amplitude, c.Threshold)
+		}
+		if mode == ScaleCoefficients &&
+			math.Abs(amplitude-c.Threshold) > 1.0e-10 {
+			t.Errorf("Log amplitude = %f, want %f.", amplitude, c.Threshold)
+		}
+	}
+}
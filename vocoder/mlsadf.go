@@ -5,6 +5,7 @@ package vocoder
 type MLSAFilter struct {
 	baseFilter1 *MLSACascadeFilter
 	baseFilter2 *MLSACascadeFilter
+	delayLines
 }
 
 // MLSACascadeFilter represents a cascade filter which contains MLSA base filters.
@@ -12,6 +13,7 @@ type MLSACascadeFilter struct {
 	cascadeFilters []*MLSABaseFilter
 	padeCoef       []float64
 	delay          []float64
+	delayLines
 }
 
 // MLSABaseFilter represents a base filter of the MLSA digital filter.
@@ -21,16 +23,21 @@ type MLSABaseFilter struct {
 	Alpha float64
 
 	delay []float64
+	delayLines
 }
 
-// NewMLSABaseFilter returns its instance.
+// NewMLSABaseFilter returns its instance and error.
 // It requires the order of mel-cepstrum and all-pass constant (alpha).
-func NewMLSABaseFilter(order int, alpha float64) *MLSABaseFilter {
+func NewMLSABaseFilter(order int, alpha float64) (*MLSABaseFilter, error) {
+	if err := validateFilterParams(order, alpha); err != nil {
+		return nil, err
+	}
 	mlsaBase := new(MLSABaseFilter)
 	mlsaBase.Order = order
 	mlsaBase.Alpha = alpha
 	mlsaBase.delay = make([]float64, order+1)
-	return mlsaBase
+	mlsaBase.delayLines = newDelayLines(mlsaBase.delay)
+	return mlsaBase, nil
 }
 
 // Filter returns filtered sample given the inpuy sample and filter coeffficients.
@@ -57,40 +64,67 @@ func (bf *MLSABaseFilter) Filter(sample float64, filterCoef []float64) float64 {
 	return result
 }
 
-// NewMLSABaseFilter returns its instance and error with
+// Clone returns a deep copy of the filter.
+func (bf *MLSABaseFilter) Clone() *MLSABaseFilter {
+	clone := *bf
+	clone.delay = cloneDelay(bf.delay)
+	clone.delayLines = newDelayLines(clone.delay)
+	return &clone
+}
+
+// NewMLSACascadeFilter returns its instance and error with
 // the order of mel-cepstrum, all-pass constant (alpha) and the order
 // of pade approximation.
-// Order of pade approximation 4 or 5 is only supported.
+// Order of pade approximation from 4 to 7 is supported.
 // If other one is specified, it returns non-nil error.
-func NewMLSACascadeFilter(order int, alpha float64, orderOfPade int) *MLSACascadeFilter {
+func NewMLSACascadeFilter(order int, alpha float64,
+	orderOfPade int) (*MLSACascadeFilter, error) {
+	padeCoef, err := PadeCoefficients(orderOfPade)
+	if err != nil {
+		return nil, err
+	}
+
 	cf := new(MLSACascadeFilter)
 	cf.cascadeFilters = make([]*MLSABaseFilter, orderOfPade+1)
 	cf.delay = make([]float64, orderOfPade+1)
-	cf.padeCoef = make([]float64, orderOfPade+1)
+	cf.padeCoef = padeCoef
 
 	for i := 0; i <= orderOfPade; i++ {
-		cf.cascadeFilters[i] = NewMLSABaseFilter(order, alpha)
+		cf.cascadeFilters[i], err = NewMLSABaseFilter(order, alpha)
+		if err != nil {
+			return nil, err
+		}
 	}
+	cf.delayLines = cf.collectDelayLines()
 
+	return cf, nil
+}
+
+// PadeCoefficients returns coefficients of the pade approximation of the
+// exponential function for a given order. Coefficients of order 4 and 5
+// are the modified ones used in SPTK, and those of higher orders are given
+// by A(L, l) = (2L-l)!L!/((2L)!l!(L-l)!).
+func PadeCoefficients(orderOfPade int) ([]float64, error) {
 	switch orderOfPade {
 	case 4:
-		cf.padeCoef[0] = 1.0
-		cf.padeCoef[1] = 4.999273e-1
-		cf.padeCoef[2] = 1.067005e-1
-		cf.padeCoef[3] = 1.170221e-2
-		cf.padeCoef[4] = 5.656279e-4
+		return []float64{1.0, 4.999273e-1, 1.067005e-1, 1.170221e-2,
+			5.656279e-4}, nil
 	case 5:
-		cf.padeCoef[0] = 1.0
-		cf.padeCoef[1] = 4.999391e-1
-		cf.padeCoef[2] = 1.107098e-1
-		cf.padeCoef[3] = 1.369984e-2
-		cf.padeCoef[4] = 9.564853e-4
-		cf.padeCoef[5] = 3.041721e-5
+		return []float64{1.0, 4.999391e-1, 1.107098e-1, 1.369984e-2,
+			9.564853e-4, 3.041721e-5}, nil
+	case 6, 7:
+		// A(L, l) = A(L, l-1) (L-l+1) / (l (2L-l+1))
+		L := orderOfPade
+		padeCoef := make([]float64, L+1)
+		padeCoef[0] = 1.0
+		for l := 1; l <= L; l++ {
+			padeCoef[l] = padeCoef[l-1] * float64(L-l+1) /
+				float64(l*(2*L-l+1))
+		}
+		return padeCoef, nil
 	default:
-		panic("MLSA digital filter: Order of pade approximation must be 4 or 5")
+		return nil, ErrInvalidOrderOfPade
 	}
-
-	return cf
 }
 
 // Filter returns filtered sample given the inpuy sample and filter coeffficients.
@@ -114,19 +148,56 @@ func (cf *MLSACascadeFilter) Filter(sample float64, filterCoef []float64) float6
 	return result
 }
 
+// Clone returns a deep copy of the filter.
+func (cf *MLSACascadeFilter) Clone() *MLSACascadeFilter {
+	clone := &MLSACascadeFilter{
+		cascadeFilters: make([]*MLSABaseFilter, len(cf.cascadeFilters)),
+		padeCoef:       cloneDelay(cf.padeCoef),
+		delay:          cloneDelay(cf.delay),
+	}
+	for i, bf := range cf.cascadeFilters {
+		clone.cascadeFilters[i] = bf.Clone()
+	}
+	clone.delayLines = clone.collectDelayLines()
+	return clone
+}
+
+// collectDelayLines returns the delay lines of the filter followed by
+// those of the base filters.
+func (cf *MLSACascadeFilter) collectDelayLines() delayLines {
+	lines := [][]float64{cf.delay}
+	for _, bf := range cf.cascadeFilters {
+		lines = append(lines, bf.lines...)
+	}
+	return newDelayLines(lines...)
+}
+
 // NewMLSAFilter returns its instance and error.
 // It requires the order of mel-cepstrum, all-pass constant (alpha)
 // and the order of pade approximation.
-func NewMLSAFilter(order int, alpha float64, orderOfPade int) *MLSAFilter {
+func NewMLSAFilter(order int, alpha float64,
+	orderOfPade int) (*MLSAFilter, error) {
+	if err := validateFilterParams(order, alpha); err != nil {
+		return nil, err
+	}
+
+	var err error
 	mlsa := new(MLSAFilter)
 
 	// First stage filter
-	mlsa.baseFilter1 = NewMLSACascadeFilter(2, alpha, orderOfPade)
+	mlsa.baseFilter1, err = NewMLSACascadeFilter(2, alpha, orderOfPade)
+	if err != nil {
+		return nil, err
+	}
 
 	// Second stage filter
-	mlsa.baseFilter2 = NewMLSACascadeFilter(order+1, alpha, orderOfPade)
+	mlsa.baseFilter2, err = NewMLSACascadeFilter(order+1, alpha, orderOfPade)
+	if err != nil {
+		return nil, err
+	}
+	mlsa.delayLines = mlsa.collectDelayLines()
 
-	return mlsa
+	return mlsa, nil
 }
 
 // Filter returns filtered sample given the input and MLSA filter coefficients.
@@ -137,3 +208,21 @@ func (f *MLSAFilter) Filter(sample float64, filterCoef []float64) float64 {
 	// two stage cascade filtering
 	return f.baseFilter2.Filter(f.baseFilter1.Filter(sample, firstStageCoef), filterCoef)
 }
+
+// Clone returns a deep copy of the filter.
+func (f *MLSAFilter) Clone() *MLSAFilter {
+	clone := &MLSAFilter{
+		baseFilter1: f.baseFilter1.Clone(),
+		baseFilter2: f.baseFilter2.Clone(),
+	}
+	clone.delayLines = clone.collectDelayLines()
+	return clone
+}
+
+// collectDelayLines returns the delay lines of the two cascade filters.
+func (f *MLSAFilter) collectDelayLines() delayLines {
+	var lines [][]float64
+	lines = append(lines, f.baseFilter1.lines...)
+	lines = append(lines, f.baseFilter2.lines...)
+	return newDelayLines(lines...)
+}
@@ -12,46 +12,54 @@ type MLSASpeechSynthesizer struct {
 	Alpha      float64 // all-pass constant
 }
 
-// NewMLSASpeechSynthesizer returns its instance given parameters.
+// NewMLSASpeechSynthesizer returns its instance and error given parameters.
 func NewMLSASpeechSynthesizer(numMceps int, alpha float64, orderOfPade int,
-	frameShift int) *MLSASpeechSynthesizer {
+	frameShift int) (*MLSASpeechSynthesizer, error) {
+	coreFilter, err := NewMLSAFilter(numMceps, alpha, orderOfPade)
+	if err != nil {
+		return nil, err
+	}
+
 	synthesizer := new(MLSASpeechSynthesizer)
 
-	synthesizer.CoreFilter = NewMLSAFilter(numMceps, alpha, orderOfPade)
+	synthesizer.CoreFilter = coreFilter
 	synthesizer.FrameShift = frameShift
 	synthesizer.Alpha = alpha
 
-	return synthesizer
+	return synthesizer, nil
 }
 
-// Synthesis synthesizes a speech signal from an excitation signal and
-// corresponding mel-ceptrum sequence.
-func (s *MLSASpeechSynthesizer) Synthesis(excite []float64,
-	mcepSequence [][]float64) []float64 {
-	// synthesized speech signal will be stored
-	synthesizedSpeech := make([]float64, len(excite))
-
-	previousMcep := mcepSequence[0]
-	for i, currentMcep := range mcepSequence {
-		if i > 0 {
-			previousMcep = mcepSequence[i-1]
-		}
+// Reset clears the state of the filter, which is called at the beginning
+// of Synthesis.
+func (s *MLSASpeechSynthesizer) Reset() {
+	s.CoreFilter.Reset()
+}
 
-		startIndex, endIndex := i*s.FrameShift, (i+1)*s.FrameShift
-		if endIndex >= len(excite) {
-			break
-		}
+// Snapshot returns the current state of the filter, which can be used to
+// resume synthesis from the current frame.
+func (s *MLSASpeechSynthesizer) Snapshot() FilterState {
+	return s.CoreFilter.Snapshot()
+}
 
-		// Synthesize a part of speech
-		partOfSpeech := s.SynthesisOneFrame(excite[startIndex:endIndex],
-			previousMcep, currentMcep)
+// Restore restores a state returned by Snapshot.
+func (s *MLSASpeechSynthesizer) Restore(state FilterState) error {
+	return s.CoreFilter.Restore(state)
+}
 
-		for j, val := range partOfSpeech {
-			synthesizedSpeech[i*s.FrameShift+j] = val
-		}
-	}
+// Clone returns a deep copy of the synthesizer, which can be used in
+// parallel with the original.
+func (s *MLSASpeechSynthesizer) Clone() *MLSASpeechSynthesizer {
+	clone := *s
+	clone.CoreFilter = s.CoreFilter.Clone()
+	return &clone
+}
 
-	return synthesizedSpeech
+// Synthesis synthesizes a speech signal from an excitation signal and
+// corresponding mel-ceptrum sequence. The state of the filter is reset
+// before synthesis.
+func (s *MLSASpeechSynthesizer) Synthesis(excite []float64,
+	mcepSequence [][]float64) []float64 {
+	return synthesize(s, s.FrameShift, excite, mcepSequence)
 }
 
 // SynthesisOneFrame synthesizes a part of speech signal from an excitation signal
@@ -60,31 +68,11 @@ func (s *MLSASpeechSynthesizer) Synthesis(excite []float64,
 // interpolated.
 func (s *MLSASpeechSynthesizer) SynthesisOneFrame(excite []float64,
 	previousMcep, currentMcep []float64) []float64 {
-	// Convert to MLSA filter coefficients from Mel-cepstrum
-	currentFilterCoef := MCep2MLSAFilterCoef(currentMcep, s.Alpha)
+	// Convert to MLSA filter coefficients from mel-cepstrum, whose first
+	// coefficient is the log gain
 	previousFilterCoef := MCep2MLSAFilterCoef(previousMcep, s.Alpha)
+	currentFilterCoef := MCep2MLSAFilterCoef(currentMcep, s.Alpha)
 
-	// Compute slope
-	slope := make([]float64, len(currentMcep))
-	for i := 0; i < len(slope); i++ {
-		slope[i] = (currentFilterCoef[i] - previousFilterCoef[i]) /
-			float64(len(excite))
-	}
-
-	partOfSpeech := make([]float64, len(excite))
-	linearlyInterpolatedCoef := make([]float64, len(previousFilterCoef))
-	copy(linearlyInterpolatedCoef, previousFilterCoef)
-	for i := 0; i < len(excite); i++ {
-		// Multyply power coeffcient
-		scaledExcitation := excite[i] * math.Exp(linearlyInterpolatedCoef[0])
-		// Filtering
-		partOfSpeech[i] = s.CoreFilter.Filter(scaledExcitation,
-			linearlyInterpolatedCoef)
-		// Linear interpolation of filter coefficients
-		for j := 0; j < len(slope); j++ {
-			linearlyInterpolatedCoef[j] += slope[j]
-		}
-	}
-
-	return partOfSpeech
+	return synthesizeFrame(excite, previousFilterCoef, currentFilterCoef,
+		math.Exp, s.CoreFilter.Filter)
 }
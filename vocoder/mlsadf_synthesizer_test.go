@@ -53,7 +53,10 @@ func TestMLSASynthesis(t *testing.T) {
 	ex = g.Generate(f0Seq)
 
 	// Waveform generation
-	synth := NewMLSASpeechSynthesizer(order, alpha, pd, frameShift)
+	synth, err := NewMLSASpeechSynthesizer(order, alpha, pd, frameShift)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	_ = synth.Synthesis(ex, mc)
 	// TODO(ryuichi) valid check
@@ -36,7 +36,10 @@ func TestMLSAConsistencyWithSPTK(t *testing.T) {
 	mc := mgcep.MCep(window.BlackmanNormalized(data), order, alpha)
 	filterCoef := MCep2MLSAFilterCoef(mc, alpha)
 
-	mf := NewMLSAFilter(order, alpha, pd)
+	mf, err := NewMLSAFilter(order, alpha, pd)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// tricky allocation based on the SPTK (just for test)
 	d := make([]float64, 3*(pd+1)+pd*(order+2))
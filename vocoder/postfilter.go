@@ -0,0 +1,99 @@
+package vocoder
+
+import (
+	"github.com/r9y9/gossp/mgcep"
+	"math"
+)
+
+// DefaultPostfilterImpulseResponseLen is the length of impulse responses
+// used to compute frame energy in postfiltering.
+const DefaultPostfilterImpulseResponseLen = 576
+
+// MCepPostfilter returns mel-cepstrum emphasized by the postfilter, which
+// alleviates over-smoothing of synthetic mel-cepstrum. Coefficients of
+// the MLSA filter except for the first two are emphasized by a factor of
+// 1+beta, and the frame energy is restored to that of the original.
+func MCepPostfilter(mcep []float64, alpha, beta float64) []float64 {
+	if len(mcep) < 3 {
+		postfiltered := make([]float64, len(mcep))
+		copy(postfiltered, mcep)
+		return postfiltered
+	}
+
+	e1 := mgcep.MCep2Energy(mcep, alpha, DefaultPostfilterImpulseResponseLen)
+
+	// Emphasis in the domain of MLSA filter coefficients
+	b := emphasizeFilterCoef(mcep, alpha, beta)
+
+	// Energy compensation
+	e2 := mgcep.MCep2Energy(B2MC(b, alpha), alpha,
+		DefaultPostfilterImpulseResponseLen)
+	b[0] += math.Log(e1/e2) / 2.0
+
+	return B2MC(b, alpha)
+}
+
+// emphasizeFilterCoef returns filter coefficients of a given (mel-)
+// cepstrum whose coefficients except for the first two are emphasized by
+// a factor of 1+beta. The second coefficient is corrected so that the
+// emphasis does not affect it after the conversion back to cepstrum.
+func emphasizeFilterCoef(c []float64, alpha, beta float64) []float64 {
+	b := MCep2MLSAFilterCoef(c, alpha)
+	b[1] -= beta * alpha * c[2]
+	for i := 2; i < len(b); i++ {
+		b[i] *= 1.0 + beta
+	}
+	return b
+}
+
+// MCepSequencePostfilter applies MCepPostfilter to each frame.
+func MCepSequencePostfilter(mcepSequence [][]float64,
+	alpha, beta float64) [][]float64 {
+	postfiltered := make([][]float64, len(mcepSequence))
+	for i, mcep := range mcepSequence {
+		postfiltered[i] = MCepPostfilter(mcep, alpha, beta)
+	}
+	return postfiltered
+}
+
+// MGCepPostfilter returns mel-generalized cepstrum emphasized by the
+// postfilter, which is the mel-generalized variant of MCepPostfilter for
+// MGLSASpeechSynthesizer. The emphasis is the same as that of
+// MCepPostfilter, and the frame energy is restored by the gain of the
+// normalized mel-generalized cepstrum, so that both postfilters agree
+// for gamma = 0.
+func MGCepPostfilter(mgc []float64, alpha, gamma, beta float64) []float64 {
+	if len(mgc) < 3 {
+		postfiltered := make([]float64, len(mgc))
+		copy(postfiltered, mgc)
+		return postfiltered
+	}
+	emphasized := B2MC(emphasizeFilterCoef(mgc, alpha, beta), alpha)
+
+	e1 := mgcepEnergy(mgc, alpha, gamma)
+	e2 := mgcepEnergy(emphasized, alpha, gamma)
+
+	// Energy is proportional to the square of the gain
+	normalized := mgcep.GNorm(emphasized, gamma)
+	normalized[0] *= math.Sqrt(e1 / e2)
+
+	return mgcep.IGNorm(normalized, gamma)
+}
+
+// MGCepSequencePostfilter applies MGCepPostfilter to each frame.
+func MGCepSequencePostfilter(mgcepSequence [][]float64,
+	alpha, gamma, beta float64) [][]float64 {
+	postfiltered := make([][]float64, len(mgcepSequence))
+	for i, mgc := range mgcepSequence {
+		postfiltered[i] = MGCepPostfilter(mgc, alpha, gamma, beta)
+	}
+	return postfiltered
+}
+
+// mgcepEnergy returns the energy of the impulse response of a given
+// mel-generalized cepstrum.
+func mgcepEnergy(mgc []float64, alpha, gamma float64) float64 {
+	mcep := mgcep.MGC2MGC(mgc, alpha, gamma,
+		DefaultPostfilterImpulseResponseLen-1, alpha, 0.0)
+	return mgcep.MCep2Energy(mcep, alpha, DefaultPostfilterImpulseResponseLen)
+}
@@ -0,0 +1,78 @@
+package vocoder
+
+import (
+	"github.com/r9y9/gossp/mgcep"
+	"math"
+	"testing"
+)
+
+var testPostfilterMCep = []float64{
+	-1.2, 1.5, 0.4, -0.3, 0.2, -0.15, 0.1, 0.05, -0.05, 0.02, -0.01,
+}
+
+func TestMCepPostfilterIdentity(t *testing.T) {
+	alpha := 0.41
+	postfiltered := MCepPostfilter(testPostfilterMCep, alpha, 0.0)
+	for i, val := range postfiltered {
+		if math.Abs(val-testPostfilterMCep[i]) > 1.0e-10 {
+			t.Errorf("Postfiltered[%d] = %f with beta = 0, want %f.",
+				i, val, testPostfilterMCep[i])
+		}
+	}
+}
+
+func TestMCepPostfilterEnergy(t *testing.T) {
+	alpha := 0.41
+	length := DefaultPostfilterImpulseResponseLen
+	postfiltered := MCepPostfilter(testPostfilterMCep, alpha, 0.4)
+
+	e1 := mgcep.MCep2Energy(testPostfilterMCep, alpha, length)
+	e2 := mgcep.MCep2Energy(postfiltered, alpha, length)
+	if math.Abs(e1-e2) > 1.0e-8*e1 {
+		t.Errorf("Energy after postfiltering = %f, want %f.", e2, e1)
+	}
+
+	for i := 3; i < len(postfiltered); i++ {
+		if math.Abs(postfiltered[i]) <= math.Abs(testPostfilterMCep[i]) {
+			t.Errorf("|Postfiltered[%d]| = %f, want > %f.", i,
+				math.Abs(postfiltered[i]), math.Abs(testPostfilterMCep[i]))
+		}
+	}
+}
+
+func TestMGCepPostfilter(t *testing.T) {
+	alpha, gamma := 0.41, -1.0/3.0
+	mgc := mgcep.MGC2MGC(testPostfilterMCep, alpha, 0.0,
+		len(testPostfilterMCep)-1, alpha, gamma)
+
+	identity := MGCepPostfilter(mgc, alpha, gamma, 0.0)
+	for i, val := range identity {
+		if math.Abs(val-mgc[i]) > 1.0e-10 {
+			t.Errorf("Postfiltered[%d] = %f with beta = 0, want %f.",
+				i, val, mgc[i])
+		}
+	}
+
+	postfiltered := MGCepPostfilter(mgc, alpha, gamma, 0.4)
+	e1 := mgcepEnergy(mgc, alpha, gamma)
+	e2 := mgcepEnergy(postfiltered, alpha, gamma)
+	if math.Abs(e1-e2) > 1.0e-8*e1 {
+		t.Errorf("Energy after postfiltering = %f, want %f.", e2, e1)
+	}
+	for i := 3; i < len(postfiltered); i++ {
+		if math.Abs(postfiltered[i]) <= math.Abs(mgc[i]) {
+			t.Errorf("|Postfiltered[%d]| = %f, want > %f.", i,
+				math.Abs(postfiltered[i]), math.Abs(mgc[i]))
+		}
+	}
+
+	// Agreement with MCepPostfilter for gamma = 0
+	expected := MCepPostfilter(testPostfilterMCep, alpha, 0.4)
+	postfiltered = MGCepPostfilter(testPostfilterMCep, alpha, 0.0, 0.4)
+	for i := range expected {
+		if math.Abs(postfiltered[i]-expected[i]) > 1.0e-10 {
+			t.Errorf("Postfiltered[%d] = %f with gamma = 0, want %f.",
+				i, postfiltered[i], expected[i])
+		}
+	}
+}
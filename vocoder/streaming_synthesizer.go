@@ -0,0 +1,94 @@
+package vocoder
+
+import (
+	"github.com/r9y9/gossp/excite"
+)
+
+// FrameSynthesizer is the interface that wraps the SynthesisOneFrame and
+// Reset methods, which is implemented by MLSASpeechSynthesizer and
+// MGLSASpeechSynthesizer.
+type FrameSynthesizer interface {
+	SynthesisOneFrame(excite, previousParam, currentParam []float64) []float64
+	Reset()
+}
+
+// StreamingSynthesizer represents a speech synthesizer that accepts f0 and
+// spectral parameters frame by frame, which is suitable for low-latency
+// synthesis. The states of the excitation source and the filter are kept
+// across calls, so that the output is identical to that of batch synthesis.
+type StreamingSynthesizer struct {
+	Source      excite.Source
+	Synthesizer FrameSynthesizer
+	FrameShift  int
+
+	previousF0    float64
+	previousParam []float64
+}
+
+// NewStreamingSynthesizer returns its instance given an excitation source
+// and a frame synthesizer, which must share frameshift.
+func NewStreamingSynthesizer(source excite.Source,
+	synthesizer FrameSynthesizer, frameShift int) *StreamingSynthesizer {
+	return &StreamingSynthesizer{
+		Source:      source,
+		Synthesizer: synthesizer,
+		FrameShift:  frameShift,
+	}
+}
+
+// NewMLSAStreamingSynthesizer returns a streaming synthesizer based on the
+// pulse excitation and the MLSA filter, and error.
+func NewMLSAStreamingSynthesizer(sampleRate, frameShift, order int,
+	alpha float64, orderOfPade int) (*StreamingSynthesizer, error) {
+	synthesizer, err := NewMLSASpeechSynthesizer(order, alpha, orderOfPade,
+		frameShift)
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamingSynthesizer(
+		excite.NewPulseExcite(sampleRate, frameShift),
+		synthesizer, frameShift), nil
+}
+
+// NewMGLSAStreamingSynthesizer returns a streaming synthesizer based on the
+// pulse excitation and the MGLSA filter, and error.
+func NewMGLSAStreamingSynthesizer(sampleRate, frameShift, order int,
+	alpha float64, numStage int) (*StreamingSynthesizer, error) {
+	synthesizer, err := NewMGLSASpeechSynthesizer(order, alpha, numStage,
+		frameShift)
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamingSynthesizer(
+		excite.NewPulseExcite(sampleRate, frameShift),
+		synthesizer, frameShift), nil
+}
+
+// Push synthesizes FrameShift samples from f0 and spectral parameters
+// (e.g. mel-cepstrum) of the next frame. Parameters are interpolated from
+// the previous frame as with batch synthesis.
+func (s *StreamingSynthesizer) Push(f0 float64, param []float64) []float64 {
+	if s.previousParam == nil {
+		s.previousF0 = f0
+		s.previousParam = param
+	}
+
+	exciteForFrame := s.Source.GenerateOneFrame(s.previousF0, f0)
+	synthesized := s.Synthesizer.SynthesisOneFrame(exciteForFrame,
+		s.previousParam, param)
+
+	s.previousF0 = f0
+	s.previousParam = make([]float64, len(param))
+	copy(s.previousParam, param)
+
+	return synthesized
+}
+
+// Reset resets the excitation source and the filter and forgets the
+// previous frame, so that the next frame is regarded as the first one.
+func (s *StreamingSynthesizer) Reset() {
+	s.Source.Reset()
+	s.Synthesizer.Reset()
+	s.previousF0 = 0.0
+	s.previousParam = nil
+}
@@ -0,0 +1,109 @@
+package vocoder
+
+import (
+	"github.com/r9y9/gossp/excite"
+	"math"
+	"testing"
+)
+
+func createTestF0AndParams(numFrames, order int) ([]float64, [][]float64) {
+	f0Sequence := make([]float64, numFrames)
+	params := make([][]float64, numFrames)
+	for i := range f0Sequence {
+		if i%20 >= 5 {
+			f0Sequence[i] = 120.0 + 20.0*math.Sin(float64(i)/10.0)
+		}
+		params[i] = make([]float64, order+1)
+		params[i][0] = -1.0 + 0.1*math.Cos(float64(i)/7.0)
+		for j := 1; j <= order; j++ {
+			params[i][j] = 0.3 * math.Sin(float64(i*j)/13.0) / float64(j)
+		}
+	}
+	return f0Sequence, params
+}
+
+func testStreamingSynthesis(t *testing.T, batch []float64,
+	s *StreamingSynthesizer, f0Sequence []float64, params [][]float64) {
+	frameShift := s.FrameShift
+	for i := range f0Sequence {
+		synthesized := s.Push(f0Sequence[i], params[i])
+		if len(synthesized) != frameShift {
+			t.Fatalf("Length of frame %d = %d, want %d.",
+				i, len(synthesized), frameShift)
+		}
+		for j, val := range synthesized {
+			expected := batch[i*frameShift+j]
+			if math.Abs(val-expected) > 1.0e-10 {
+				t.Fatalf("Sample %d = %f, want %f.", i*frameShift+j, val, expected)
+			}
+		}
+	}
+
+	// The last frame is also synthesized
+	energy := 0.0
+	for _, val := range batch[len(batch)-frameShift:] {
+		energy += val * val
+	}
+	if energy == 0.0 || math.IsNaN(energy) {
+		t.Errorf("Energy of the last frame = %f, want positive.", energy)
+	}
+}
+
+func TestMLSAStreamingSynthesis(t *testing.T) {
+	sampleRate, frameShift, order, alpha := 16000, 80, 24, 0.41
+	f0Sequence, mcepSequence := createTestF0AndParams(60, order)
+
+	ex := excite.NewPulseExcite(sampleRate, frameShift).Generate(f0Sequence)
+	synth, err := NewMLSASpeechSynthesizer(order, alpha, 5, frameShift)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := synth.Synthesis(ex, mcepSequence)
+
+	s, err := NewMLSAStreamingSynthesizer(sampleRate, frameShift, order, alpha, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStreamingSynthesis(t, batch, s, f0Sequence, mcepSequence)
+}
+
+func TestMGLSAStreamingSynthesis(t *testing.T) {
+	sampleRate, frameShift, order, alpha, numStage := 16000, 80, 24, 0.41, 3
+	f0Sequence, mgcepSequence := createTestF0AndParams(60, order)
+
+	ex := excite.NewPulseExcite(sampleRate, frameShift).Generate(f0Sequence)
+	synth, err := NewMGLSASpeechSynthesizer(order, alpha, numStage, frameShift)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := synth.Synthesis(ex, mgcepSequence)
+
+	s, err := NewMGLSAStreamingSynthesizer(sampleRate, frameShift, order, alpha,
+		numStage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStreamingSynthesis(t, batch, s, f0Sequence, mgcepSequence)
+}
+
+func TestSynthesisEmptyParamSequence(t *testing.T) {
+	order, alpha, frameShift := 24, 0.41, 80
+	synth, err := NewMLSASpeechSynthesizer(order, alpha, 5, frameShift)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ex := make([]float64, 3*frameShift)
+	for i := range ex {
+		ex[i] = 1.0
+	}
+
+	synthesized := synth.Synthesis(ex, [][]float64{})
+	if len(synthesized) != len(ex) {
+		t.Fatalf("Length = %d, want %d.", len(synthesized), len(ex))
+	}
+	for i, val := range synthesized {
+		if val != 0.0 {
+			t.Errorf("Sample %d = %f, want 0.", i, val)
+		}
+	}
+}
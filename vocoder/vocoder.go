@@ -1,2 +1,97 @@
 // Package vocoder provides support for vocoding.
 package vocoder
+
+import (
+	"errors"
+)
+
+const (
+	MinOrderOfPade = 4
+	MaxOrderOfPade = 7
+)
+
+var (
+	ErrInvalidOrder       = errors.New("vocoder: order must be non-negative")
+	ErrInvalidAlpha       = errors.New("vocoder: all-pass constant must be in (-1, 1)")
+	ErrInvalidOrderOfPade = errors.New("vocoder: order of pade approximation must be from 4 to 7")
+	ErrInvalidNumStage    = errors.New("vocoder: number of stages must be positive")
+)
+
+// validateFilterParams returns an error if the order of cepstrum or the
+// all-pass constant is invalid.
+func validateFilterParams(order int, alpha float64) error {
+	if order < 0 {
+		return ErrInvalidOrder
+	}
+	if alpha <= -1.0 || alpha >= 1.0 {
+		return ErrInvalidAlpha
+	}
+	return nil
+}
+
+// synthesize resets a synthesizer and synthesizes a speech signal frame by
+// frame from an excitation signal and corresponding parameter sequence.
+func synthesize(s FrameSynthesizer, frameShift int, excite []float64,
+	paramSequence [][]float64) []float64 {
+	// synthesized speech signal will be stored
+	synthesizedSpeech := make([]float64, len(excite))
+	if len(paramSequence) == 0 {
+		return synthesizedSpeech
+	}
+
+	s.Reset()
+	previousParam := paramSequence[0]
+	for i, currentParam := range paramSequence {
+		if i > 0 {
+			previousParam = paramSequence[i-1]
+		}
+
+		startIndex, endIndex := i*frameShift, (i+1)*frameShift
+		if endIndex > len(excite) {
+			break
+		}
+
+		// Synthesize a part of speech
+		partOfSpeech := s.SynthesisOneFrame(excite[startIndex:endIndex],
+			previousParam, currentParam)
+		copy(synthesizedSpeech[startIndex:], partOfSpeech)
+	}
+
+	return synthesizedSpeech
+}
+
+// synthesizeFrame filters an excitation signal with coefficients linearly
+// interpolated between succesive two frames, where the excitation is
+// multiplied by the gain given by the first coefficient.
+func synthesizeFrame(excite, previousCoef, currentCoef []float64,
+	gain func(float64) float64,
+	filter func(float64, []float64) float64) []float64 {
+	// Compute slope
+	slope := make([]float64, len(currentCoef))
+	for i := 0; i < len(slope); i++ {
+		slope[i] = (currentCoef[i] - previousCoef[i]) / float64(len(excite))
+	}
+
+	partOfSpeech := make([]float64, len(excite))
+	linearlyInterpolatedCoef := make([]float64, len(previousCoef))
+	copy(linearlyInterpolatedCoef, previousCoef)
+	for i := 0; i < len(excite); i++ {
+		// Multiply gain
+		scaledExcitation := excite[i] * gain(linearlyInterpolatedCoef[0])
+		// Filtering
+		partOfSpeech[i] = filter(scaledExcitation, linearlyInterpolatedCoef)
+		// Linear interpolation of filter coefficients
+		for j := 0; j < len(slope); j++ {
+			linearlyInterpolatedCoef[j] += slope[j]
+		}
+	}
+
+	return partOfSpeech
+}
+
+// linearGain returns the first coefficient as the gain, which is the case
+// of LPC, LSP and PARCOR. MLSA and MGLSA filter coefficients have the log
+// gain instead, for which math.Exp is used.
+func linearGain(k float64) float64 {
+	return k
+}
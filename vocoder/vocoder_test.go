@@ -0,0 +1,90 @@
+package vocoder
+
+import (
+	"github.com/r9y9/gossp/mgcep"
+	"math"
+	"testing"
+)
+
+func TestPadeCoefficients(t *testing.T) {
+	for orderOfPade := MinOrderOfPade; orderOfPade <= MaxOrderOfPade; orderOfPade++ {
+		padeCoef, err := PadeCoefficients(orderOfPade)
+		if err != nil {
+			t.Fatalf("PadeCoefficients(%d) returns %v, want nil.",
+				orderOfPade, err)
+		}
+		if len(padeCoef) != orderOfPade+1 {
+			t.Errorf("Length = %d, want %d.", len(padeCoef), orderOfPade+1)
+		}
+		if padeCoef[0] != 1.0 || math.Abs(padeCoef[1]-0.5) > 1.0e-4 {
+			t.Errorf("PadeCoefficients(%d) = %v, want [1 0.5 ...].",
+				orderOfPade, padeCoef)
+		}
+	}
+
+	// A(6, 6) = 6!6!/12!
+	padeCoef, _ := PadeCoefficients(6)
+	if expected := 1.0 / 665280.0; math.Abs(padeCoef[6]-expected) > 1.0e-15 {
+		t.Errorf("A(6, 6) = %g, want %g.", padeCoef[6], expected)
+	}
+}
+
+func TestMLSAFilterWithPade(t *testing.T) {
+	order, alpha, length := 24, 0.41, 512
+	_, params := createTestF0AndParams(2, order)
+
+	coef := MCep2MLSAFilterCoef(params[1], alpha)
+	coef[0] = 0.0
+
+	// Impulse response of exp(F(z)), which the MLSA filter approximates
+	expected := mgcep.C2IR(mgcep.FreqT(B2MC(coef, alpha), length-1, -alpha),
+		length)
+
+	for orderOfPade := MinOrderOfPade; orderOfPade <= MaxOrderOfPade; orderOfPade++ {
+		f, err := NewMLSAFilter(order, alpha, orderOfPade)
+		if err != nil {
+			t.Fatal(err)
+		}
+		maxErr := 0.0
+		for i := 0; i < length; i++ {
+			x := 0.0
+			if i == 0 {
+				x = 1.0
+			}
+			maxErr = math.Max(maxErr, math.Abs(f.Filter(x, coef)-expected[i]))
+		}
+		if maxErr > 1.0e-3 {
+			t.Errorf("Error of impulse response with pade order %d = %g, want < %g.",
+				orderOfPade, maxErr, 1.0e-3)
+		}
+	}
+}
+
+func TestConstructorErrors(t *testing.T) {
+	if _, err := NewMLSAFilter(24, 0.41, 3); err != ErrInvalidOrderOfPade {
+		t.Errorf("NewMLSAFilter returns %v, want %v.", err, ErrInvalidOrderOfPade)
+	}
+	if _, err := NewMLSAFilter(24, 0.41, 8); err != ErrInvalidOrderOfPade {
+		t.Errorf("NewMLSAFilter returns %v, want %v.", err, ErrInvalidOrderOfPade)
+	}
+	if _, err := NewMLSAFilter(24, 1.0, 5); err != ErrInvalidAlpha {
+		t.Errorf("NewMLSAFilter returns %v, want %v.", err, ErrInvalidAlpha)
+	}
+	if _, err := NewMLSAFilter(-1, 0.41, 5); err != ErrInvalidOrder {
+		t.Errorf("NewMLSAFilter returns %v, want %v.", err, ErrInvalidOrder)
+	}
+	if _, err := NewMLSASpeechSynthesizer(24, -1.0, 5, 80); err != ErrInvalidAlpha {
+		t.Errorf("NewMLSASpeechSynthesizer returns %v, want %v.",
+			err, ErrInvalidAlpha)
+	}
+	if _, err := NewMGLSAFilter(24, 0.41, 0); err != ErrInvalidNumStage {
+		t.Errorf("NewMGLSAFilter returns %v, want %v.", err, ErrInvalidNumStage)
+	}
+	if _, err := NewMGLSASpeechSynthesizer(24, 1.5, 3, 80); err != ErrInvalidAlpha {
+		t.Errorf("NewMGLSASpeechSynthesizer returns %v, want %v.",
+			err, ErrInvalidAlpha)
+	}
+	if _, err := NewMLSAChecker(8, NoStabilization); err != ErrInvalidOrderOfPade {
+		t.Errorf("NewMLSAChecker returns %v, want %v.", err, ErrInvalidOrderOfPade)
+	}
+}
@@ -59,6 +59,14 @@ func HanningNormalized(input []float64) []float64 {
 	return WindowingNormalized(input, CreateHanning(len(input)))
 }
 
+func Nuttall(input []float64) []float64 {
+	return Windowing(input, CreateNuttall(len(input)))
+}
+
+func NuttallNormalized(input []float64) []float64 {
+	return WindowingNormalized(input, CreateNuttall(len(input)))
+}
+
 func Gaussian(input []float64, stddev float64) []float64 {
 	return Windowing(input, CreateGaussian(len(input), stddev))
 }
@@ -40,6 +40,20 @@ func CreateHamming(length int) []float64 {
 	return hamming
 }
 
+// CreateNuttall returns nuttall window function.
+func CreateNuttall(length int) []float64 {
+	nuttall := make([]float64, length)
+
+	arg := 2.0 * math.Pi / float64(length-1)
+	for i := range nuttall {
+		x := arg * float64(i)
+		nuttall[i] = 0.355768 - 0.487396*math.Cos(x) +
+			0.144232*math.Cos(2.0*x) - 0.012604*math.Cos(3.0*x)
+	}
+
+	return nuttall
+}
+
 // CreateGaussian returns Gaussian window function.
 // It is recommended that stddev (standard deviation) is set to around 0.4.
 func CreateGaussian(length int, stddev float64) []float64 {
@@ -54,6 +54,17 @@ func TestHamming(t *testing.T) {
 	testSideValue(t, result, 1.0e-1)
 }
 
+func TestNuttall(t *testing.T) {
+	length := 512
+	dummyInput := createUniformVector(length)
+
+	result := Nuttall(dummyInput)
+	testSideValue(t, result, 1.0e-3)
+
+	result = NuttallNormalized(dummyInput)
+	testSideValue(t, result, 1.0e-3)
+}
+
 func TestGaussian(t *testing.T) {
 	length := 512
 	dummyInput := createUniformVector(length)
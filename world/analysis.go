@@ -0,0 +1,84 @@
+package world
+
+import (
+	"errors"
+	"github.com/r9y9/gossp/f0"
+	"github.com/r9y9/gossp/pitch"
+)
+
+const (
+	DefaultF0Ceil = 800.0
+)
+
+var (
+	ErrSampleRateMismatch = errors.New("world: sample rate of f0 contour mismatch")
+	ErrFrameShiftMismatch = errors.New("world: frame shift of f0 contour mismatch")
+	ErrNumFramesMismatch  = errors.New("world: number of frames of parameters mismatch")
+)
+
+// Parameters represents speech parameters of each frame. The n-th frame is
+// centered at n*FrameShift as with package f0.
+type Parameters struct {
+	SampleRate int
+	FrameShift int
+	F0         []float64   // f0 in Hz, which is zero for unvoiced frames
+	Spectrum   [][]float64 // Power spectral envelope (FFTSize/2+1 bins)
+	BandEdges  []float64   // Boundaries between aperiodicity bands in Hz
+	BandAp     [][]float64 // Band aperiodicity
+}
+
+// FFTSize returns the FFT size of the spectral envelope, which is zero if
+// there are no frames.
+func (p *Parameters) FFTSize() int {
+	if len(p.Spectrum) == 0 {
+		return 0
+	}
+	return (len(p.Spectrum[0]) - 1) * 2
+}
+
+// Analyzer represents decomposing speech into f0, spectral envelope and
+// band aperiodicity.
+type Analyzer struct {
+	SampleRate       int
+	FrameShift       int
+	F0Estimator      pitch.Estimator
+	CheapTrick       *CheapTrick
+	BandAperiodicity *BandAperiodicity
+}
+
+// NewAnalyzer returns a new Analyzer instance, which estimates f0 by DIO
+// with StoneMask, spectral envelope by CheapTrick and aperiodicity in
+// excite.DefaultBandEdges.
+func NewAnalyzer(sampleRate, frameShift int) *Analyzer {
+	return &Analyzer{
+		SampleRate: sampleRate,
+		FrameShift: frameShift,
+		F0Estimator: f0.NewDIOEstimator(sampleRate, frameShift,
+			DefaultF0Floor, DefaultF0Ceil),
+		CheapTrick:       NewCheapTrick(sampleRate),
+		BandAperiodicity: NewBandAperiodicity(sampleRate, nil),
+	}
+}
+
+// Analyze returns speech parameters of a given audio buffer. It returns
+// ErrSampleRateMismatch or ErrFrameShiftMismatch if the f0 contour given by
+// F0Estimator does not have the same sample rate and frame shift as the
+// analyzer.
+func (a *Analyzer) Analyze(audioBuffer []float64) (*Parameters, error) {
+	contour := a.F0Estimator.Estimate(audioBuffer)
+	if contour.SampleRate != a.SampleRate {
+		return nil, ErrSampleRateMismatch
+	}
+	if contour.FrameShift != a.FrameShift {
+		return nil, ErrFrameShiftMismatch
+	}
+
+	return &Parameters{
+		SampleRate: a.SampleRate,
+		FrameShift: a.FrameShift,
+		F0:         contour.F0,
+		Spectrum:   a.CheapTrick.Estimate(audioBuffer, contour),
+		BandEdges:  a.BandAperiodicity.BandEdges,
+		BandAp:     a.BandAperiodicity.Estimate(audioBuffer, contour),
+	}, nil
+}
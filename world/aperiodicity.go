@@ -0,0 +1,209 @@
+package world
+
+import (
+	"github.com/mjibson/go-dsp/fft"
+	"github.com/r9y9/gossp/excite"
+	"github.com/r9y9/gossp/pitch"
+	"github.com/r9y9/gossp/window"
+	"math"
+	"math/cmplx"
+)
+
+const (
+	DefaultNumPeriods = 3.0
+
+	// MinAperiodicity is the floor of estimated aperiodicity (-60 dB).
+	MinAperiodicity = 1.0e-6
+)
+
+// BandAperiodicity represents estimating band aperiodicity, which is the
+// ratio of aperiodic (noise) power to the whole power in each frequency
+// band. It is measured by the normalized cross-correlation in each band
+// between two segments one pitch period apart, which are windowed by a
+// pitch-adaptive Blackman window, so that a harmonic component gives one
+// and a noise component gives zero. The bands are compatible with
+// excite.MixedExcite.
+type BandAperiodicity struct {
+	SampleRate int
+	BandEdges  []float64 // Boundaries between bands in Hz
+	NumPeriods float64   // Window length in pitch periods
+}
+
+// NewBandAperiodicity returns a new band aperiodicity estimator with
+// boundaries between bands. If bandEdges is nil,
+// excite.DefaultBandEdges is used.
+func NewBandAperiodicity(sampleRate int, bandEdges []float64) *BandAperiodicity {
+	if bandEdges == nil {
+		bandEdges = excite.DefaultBandEdges
+	}
+	return &BandAperiodicity{
+		SampleRate: sampleRate,
+		BandEdges:  bandEdges,
+		NumPeriods: DefaultNumPeriods,
+	}
+}
+
+// NumBands returns the number of bands.
+func (b *BandAperiodicity) NumBands() int {
+	return len(b.BandEdges) + 1
+}
+
+// Estimate returns band aperiodicity of each frame of an f0 contour.
+// Aperiodicity of unvoiced frames is one in all bands.
+func (b *BandAperiodicity) Estimate(audioBuffer []float64,
+	contour *pitch.Contour) [][]float64 {
+	bandAperiodicity := make([][]float64, contour.Len())
+	for i, f := range contour.F0 {
+		position := contour.Times[i] * float64(b.SampleRate)
+		if f <= 0.0 {
+			bandAperiodicity[i] = make([]float64, b.NumBands())
+			for j := range bandAperiodicity[i] {
+				bandAperiodicity[i][j] = 1.0
+			}
+			continue
+		}
+		bandAperiodicity[i] = b.EstimateOneFrame(audioBuffer, position, f)
+	}
+	return bandAperiodicity
+}
+
+// EstimateOneFrame returns band aperiodicity at a given position in samples
+// with f0.
+func (b *BandAperiodicity) EstimateOneFrame(audioBuffer []float64,
+	position, f0 float64) []float64 {
+	fs := float64(b.SampleRate)
+	period := fs / f0
+	halfWindowLength := int(b.NumPeriods * period / 2.0)
+	windowLength := 2*halfWindowLength + 1
+	fftSize := nextPow2(windowLength)
+	w := window.CreateBlackman(windowLength)
+
+	// Two segments one pitch period apart. The fractional part of the
+	// period is compensated by a linear phase in the frequency domain.
+	intPeriod := int(period + 0.5)
+	fraction := period - float64(intPeriod)
+	center := int(position - period/2.0 + 0.5)
+	spectrum1 := windowedSpectrum(audioBuffer, center, w, fftSize)
+	spectrum2 := windowedSpectrum(audioBuffer, center+intPeriod, w, fftSize)
+
+	bandAperiodicity := make([]float64, b.NumBands())
+	for j := range bandAperiodicity {
+		low, high := 0.0, fs
+		if j > 0 {
+			low = b.BandEdges[j-1]
+		}
+		if j < len(b.BandEdges) {
+			high = b.BandEdges[j]
+		}
+
+		// Normalized cross-correlation in the band
+		cross, energy1, energy2 := 0.0, 0.0, 0.0
+		for k := 0; k <= fftSize/2; k++ {
+			frequency := float64(k) * fs / float64(fftSize)
+			if frequency < low || frequency >= high {
+				continue
+			}
+			phase := -2.0 * math.Pi * float64(k) * fraction / float64(fftSize)
+			cross += real(spectrum1[k] * cmplx.Conj(spectrum2[k]) *
+				cmplx.Rect(1.0, phase))
+			energy1 += real(spectrum1[k] * cmplx.Conj(spectrum1[k]))
+			energy2 += real(spectrum2[k] * cmplx.Conj(spectrum2[k]))
+		}
+
+		bandAperiodicity[j] = 1.0
+		if energy1 > 0.0 && energy2 > 0.0 {
+			periodicity := cross / math.Sqrt(energy1*energy2)
+			bandAperiodicity[j] = math.Max(MinAperiodicity,
+				math.Min(1.0-periodicity, 1.0))
+		}
+	}
+
+	return bandAperiodicity
+}
+
+// windowedSpectrum returns the spectrum of a DC-removed segment centered at
+// a given position, which is windowed by w.
+func windowedSpectrum(audioBuffer []float64, center int, w []float64,
+	fftSize int) []complex128 {
+	start := center - len(w)/2
+	mean := 0.0
+	for k := range w {
+		mean += sampleAt(audioBuffer, start+k)
+	}
+	mean /= float64(len(w))
+
+	frame := make([]float64, fftSize)
+	for k, val := range w {
+		frame[k] = (sampleAt(audioBuffer, start+k) - mean) * val
+	}
+	return fft.FFTReal(frame)
+}
+
+// CodeAperiodicity returns a small number of coefficients that represent
+// band aperiodicity of each frame, which are the low-order coefficients of
+// the orthogonal DCT of band aperiodicity in dB.
+func CodeAperiodicity(bandAperiodicity [][]float64,
+	numCoefficients int) [][]float64 {
+	coded := make([][]float64, len(bandAperiodicity))
+	for i, ap := range bandAperiodicity {
+		db := make([]float64, len(ap))
+		for j, val := range ap {
+			db[j] = 10.0 * math.Log10(math.Max(val, MinAperiodicity))
+		}
+		coded[i] = dctOrthogonal(db)
+		if numCoefficients < len(coded[i]) {
+			coded[i] = coded[i][:numCoefficients]
+		}
+	}
+	return coded
+}
+
+// DecodeAperiodicity returns band aperiodicity of numBands bands from the
+// coefficients given by CodeAperiodicity.
+func DecodeAperiodicity(coded [][]float64, numBands int) [][]float64 {
+	bandAperiodicity := make([][]float64, len(coded))
+	for i, c := range coded {
+		coefficients := make([]float64, numBands)
+		copy(coefficients, c)
+		bandAperiodicity[i] = idctOrthogonal(coefficients)
+		for j, val := range bandAperiodicity[i] {
+			bandAperiodicity[i][j] = math.Max(MinAperiodicity,
+				math.Min(math.Pow(10.0, val/10.0), 1.0))
+		}
+	}
+	return bandAperiodicity
+}
+
+// dctOrthogonal returns orthogonal DCT-II coefficients. Unlike package dct,
+// it accepts any length since the number of bands is usually small and odd.
+func dctOrthogonal(x []float64) []float64 {
+	n := len(x)
+	y := make([]float64, n)
+	for k := range y {
+		for i, val := range x {
+			y[k] += val * math.Cos(math.Pi*float64(k)*(float64(i)+0.5)/float64(n))
+		}
+		if k == 0 {
+			y[k] *= math.Sqrt(1.0 / float64(n))
+		} else {
+			y[k] *= math.Sqrt(2.0 / float64(n))
+		}
+	}
+	return y
+}
+
+// idctOrthogonal returns the inverse of dctOrthogonal.
+func idctOrthogonal(y []float64) []float64 {
+	n := len(y)
+	x := make([]float64, n)
+	for i := range x {
+		for k, val := range y {
+			c := math.Sqrt(2.0 / float64(n))
+			if k == 0 {
+				c = math.Sqrt(1.0 / float64(n))
+			}
+			x[i] += c * val * math.Cos(math.Pi*float64(k)*(float64(i)+0.5)/float64(n))
+		}
+	}
+	return x
+}
@@ -0,0 +1,128 @@
+package world
+
+import (
+	"github.com/r9y9/gossp/pitch"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// createHarmonics returns a harmonic signal whose components are below
+// maxFreq.
+func createHarmonics(freq, maxFreq float64, sampleRate, length int) []float64 {
+	x := make([]float64, length)
+	for h := 1; float64(h)*freq < maxFreq; h++ {
+		for i := range x {
+			x[i] += math.Sin(2.0*math.Pi*freq*float64(h)*float64(i)/float64(sampleRate)) /
+				math.Sqrt(float64(h))
+		}
+	}
+	return x
+}
+
+func createConstantContour(freq float64, sampleRate, frameShift,
+	length int) *pitch.Contour {
+	f0Sequence := make([]float64, length/frameShift+1)
+	for i := range f0Sequence {
+		f0Sequence[i] = freq
+	}
+	return pitch.NewContour(f0Sequence, sampleRate, frameShift)
+}
+
+func TestBandAperiodicity(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	length := sampleRate / 2
+	freq := 150.0
+	r := rand.New(rand.NewSource(0))
+
+	// Harmonics below 1.5 kHz and noise mainly above 2 kHz
+	x := createHarmonics(freq, 1500.0, sampleRate, length)
+	noise := make([]float64, length)
+	for i := range noise {
+		noise[i] = r.NormFloat64()
+	}
+	// High-pass noise by the second difference
+	for i := 2; i < length; i++ {
+		x[i] += 0.5 * (noise[i] - 2.0*noise[i-1] + noise[i-2])
+	}
+
+	b := NewBandAperiodicity(sampleRate, []float64{2000.0, 4000.0})
+	contour := createConstantContour(freq, sampleRate, frameShift, length)
+	bandAperiodicity := b.Estimate(x, contour)
+
+	if len(bandAperiodicity) != contour.Len() {
+		t.Fatalf("The number of frames is %d, want %d.",
+			len(bandAperiodicity), contour.Len())
+	}
+
+	// skip frames near edges
+	meanNoiseAp := make([]float64, b.NumBands())
+	for i := 10; i < len(bandAperiodicity)-10; i++ {
+		ap := bandAperiodicity[i]
+		if len(ap) != b.NumBands() {
+			t.Fatalf("The number of bands is %d, want %d.", len(ap), b.NumBands())
+		}
+		if ap[0] > 0.01 {
+			t.Errorf("Frame %d: aperiodicity of the harmonic band is %f, want below 0.01.",
+				i, ap[0])
+		}
+		for j := 1; j < len(ap); j++ {
+			if ap[j] < 0.4 {
+				t.Errorf("Frame %d: aperiodicity of the noise band %d is %f, want above 0.4.",
+					i, j, ap[j])
+			}
+			meanNoiseAp[j] += ap[j] / float64(len(bandAperiodicity)-20)
+		}
+	}
+	for j := 1; j < len(meanNoiseAp); j++ {
+		if meanNoiseAp[j] < 0.8 {
+			t.Errorf("Mean aperiodicity of the noise band %d is %f, want above 0.8.",
+				j, meanNoiseAp[j])
+		}
+	}
+}
+
+func TestBandAperiodicityUnvoiced(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	x := createHarmonics(200.0, 8000.0, sampleRate, sampleRate/10)
+
+	b := NewBandAperiodicity(sampleRate, nil)
+	contour := createConstantContour(0.0, sampleRate, frameShift, len(x))
+	for i, ap := range b.Estimate(x, contour) {
+		for j, val := range ap {
+			if val != 1.0 {
+				t.Errorf("Frame %d, band %d: aperiodicity is %f, want 1.", i, j, val)
+			}
+		}
+	}
+}
+
+func TestCodeAperiodicity(t *testing.T) {
+	bandAperiodicity := [][]float64{
+		{0.001, 0.01, 0.1, 0.5, 0.9},
+		{1.0, 1.0, 1.0, 1.0, 1.0},
+	}
+
+	// Perfect reconstruction with all coefficients
+	decoded := DecodeAperiodicity(CodeAperiodicity(bandAperiodicity, 5), 5)
+	for i := range bandAperiodicity {
+		for j := range bandAperiodicity[i] {
+			if math.Abs(decoded[i][j]-bandAperiodicity[i][j]) > 1.0e-9 {
+				t.Errorf("Frame %d, band %d: decoded %f, want %f.",
+					i, j, decoded[i][j], bandAperiodicity[i][j])
+			}
+		}
+	}
+
+	// Smooth approximation with fewer coefficients
+	coded := CodeAperiodicity(bandAperiodicity, 2)
+	if len(coded[0]) != 2 {
+		t.Errorf("The number of coefficients is %d, want 2.", len(coded[0]))
+	}
+	decoded = DecodeAperiodicity(coded, 5)
+	for j := 1; j < 5; j++ {
+		if decoded[0][j] < decoded[0][j-1] {
+			t.Errorf("Decoded aperiodicity must be increasing, %v", decoded[0])
+		}
+	}
+}
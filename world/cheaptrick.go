@@ -0,0 +1,240 @@
+package world
+
+// Reference:
+// M. Morise, "CheapTrick, a spectral envelope estimator for high-quality
+// speech synthesis," Speech Communication, vol. 67, pp.1-7, 2015.
+
+import (
+	"github.com/mjibson/go-dsp/fft"
+	"github.com/r9y9/gossp/mgcep"
+	"github.com/r9y9/gossp/pitch"
+	"math"
+)
+
+const (
+	DefaultF0Floor = 71.0
+	DefaultQ1      = -0.15
+
+	// DefaultUnvoicedF0 is f0 used to analyze unvoiced frames.
+	DefaultUnvoicedF0 = 500.0
+)
+
+// CheapTrick represents F0-adaptive spectral envelope estimation, which
+// removes the influence of pitch harmonics by a pitch-synchronous window,
+// smoothing in the frequency domain and liftering in the quefrency domain.
+type CheapTrick struct {
+	SampleRate int
+	FFTSize    int
+	Q1         float64 // Parameter for spectral recovery
+}
+
+// NewCheapTrick returns a new CheapTrick instance whose FFT size is large
+// enough to analyze DefaultF0Floor.
+func NewCheapTrick(sampleRate int) *CheapTrick {
+	return &CheapTrick{
+		SampleRate: sampleRate,
+		FFTSize:    FFTSizeForCheapTrick(sampleRate, DefaultF0Floor),
+		Q1:         DefaultQ1,
+	}
+}
+
+// FFTSizeForCheapTrick returns the FFT size required to analyze f0Floor.
+func FFTSizeForCheapTrick(sampleRate int, f0Floor float64) int {
+	return int(math.Pow(2.0,
+		1.0+math.Floor(math.Log2(3.0*float64(sampleRate)/f0Floor+1.0))))
+}
+
+// F0Floor returns the lowest f0 that can be analyzed with FFTSize. Frames
+// with lower f0 are analyzed with DefaultUnvoicedF0.
+func (c *CheapTrick) F0Floor() float64 {
+	return 3.0 * float64(c.SampleRate) / float64(c.FFTSize-3)
+}
+
+// Estimate returns a power spectral envelope (FFTSize/2+1 bins) of each
+// frame of an f0 contour.
+func (c *CheapTrick) Estimate(audioBuffer []float64,
+	contour *pitch.Contour) [][]float64 {
+	spectrogram := make([][]float64, contour.Len())
+	for i, f := range contour.F0 {
+		position := contour.Times[i] * float64(c.SampleRate)
+		spectrogram[i] = c.EstimateOneFrame(audioBuffer, position, f)
+	}
+	return spectrogram
+}
+
+// EstimateOneFrame returns a power spectral envelope at a given position in
+// samples with f0.
+func (c *CheapTrick) EstimateOneFrame(audioBuffer []float64,
+	position, f0 float64) []float64 {
+	if f0 <= c.F0Floor() {
+		f0 = DefaultUnvoicedF0
+	}
+	fs := float64(c.SampleRate)
+
+	// Pitch-synchronous Hanning window of three periods
+	halfWindowLength := int(1.5*fs/f0 + 0.5)
+	center := int(position + 0.5)
+	w := make([]float64, 2*halfWindowLength+1)
+	sum := 0.0
+	for k := range w {
+		t := float64(k-halfWindowLength) / fs
+		w[k] = 0.5*math.Cos(math.Pi*t*f0/1.5) + 0.5
+		sum += w[k] * w[k]
+	}
+	for k := range w {
+		w[k] /= math.Sqrt(sum)
+	}
+
+	// DC component is removed in proportion to the window
+	frame := make([]float64, c.FFTSize)
+	weightedSum, windowSum := 0.0, 0.0
+	for k, val := range w {
+		frame[k] = sampleAt(audioBuffer, center-halfWindowLength+k) * val
+		weightedSum += frame[k]
+		windowSum += val
+	}
+	for k, val := range w {
+		frame[k] -= val * weightedSum / windowSum
+	}
+
+	spectrum := fft.FFTReal(frame)
+	power := make([]float64, c.FFTSize/2+1)
+	for k := range power {
+		power[k] = real(spectrum[k])*real(spectrum[k]) +
+			imag(spectrum[k])*imag(spectrum[k])
+	}
+
+	power = c.dcCorrection(power, f0)
+	power = c.linearSmoothing(power, f0*2.0/3.0)
+	for k := range power {
+		power[k] += safeGuardMinimum
+	}
+	return c.smoothingWithRecovery(power, f0)
+}
+
+// dcCorrection folds the components below f0 to suppress the influence of
+// the DC component.
+func (c *CheapTrick) dcCorrection(power []float64, f0 float64) []float64 {
+	df := float64(c.SampleRate) / float64(c.FFTSize)
+	corrected := make([]float64, len(power))
+	copy(corrected, power)
+	for k := 0; float64(k)*df < f0 && k < len(power); k++ {
+		corrected[k] += interpolateSpectrum(power, (f0-float64(k)*df)/df)
+	}
+	return corrected
+}
+
+// linearSmoothing returns a spectrum smoothed by a rectangular window of a
+// given width in Hz. The spectrum is mirrored at both ends.
+func (c *CheapTrick) linearSmoothing(power []float64, width float64) []float64 {
+	df := float64(c.SampleRate) / float64(c.FFTSize)
+	margin := int(width/df) + 2
+	n := len(power)
+
+	// Mirrored spectrum, where extended[i] corresponds to bin i-margin
+	extended := make([]float64, n+2*margin)
+	for i := range extended {
+		k := i - margin
+		if k < 0 {
+			k = -k
+		}
+		if k > n-1 {
+			k = 2*(n-1) - k
+		}
+		extended[i] = power[k]
+	}
+
+	// cumulative[i] is the integral up to the upper edge of bin i-margin
+	cumulative := make([]float64, len(extended)+1)
+	for i, val := range extended {
+		cumulative[i+1] = cumulative[i] + val*df
+	}
+	integral := func(frequency float64) float64 {
+		// cumulative[i] is at frequency (i-margin-0.5)*df
+		x := frequency/df + float64(margin) + 0.5
+		i := int(math.Floor(x))
+		if i < 0 {
+			return cumulative[0]
+		}
+		if i >= len(cumulative)-1 {
+			return cumulative[len(cumulative)-1]
+		}
+		return cumulative[i] + (x-float64(i))*(cumulative[i+1]-cumulative[i])
+	}
+
+	smoothed := make([]float64, n)
+	for k := range smoothed {
+		frequency := float64(k) * df
+		smoothed[k] = (integral(frequency+width/2.0) -
+			integral(frequency-width/2.0)) / width
+	}
+	return smoothed
+}
+
+// smoothingWithRecovery smooths a log power spectrum by liftering and
+// recovers the components attenuated by the smoothing.
+func (c *CheapTrick) smoothingWithRecovery(power []float64, f0 float64) []float64 {
+	fs := float64(c.SampleRate)
+	n := len(power)
+
+	logPower := make([]float64, c.FFTSize)
+	for k := 0; k < n; k++ {
+		logPower[k] = math.Log(power[k])
+	}
+	for k := n; k < c.FFTSize; k++ {
+		logPower[k] = logPower[c.FFTSize-k]
+	}
+	ceps := fft.IFFTReal(logPower)
+
+	for i := range ceps {
+		q := i
+		if i > c.FFTSize/2 {
+			q = c.FFTSize - i
+		}
+		quefrency := float64(q) / fs
+		smoothing, recovery := 1.0, 1.0
+		if q > 0 {
+			smoothing = math.Sin(math.Pi*f0*quefrency) / (math.Pi * f0 * quefrency)
+			recovery = 1.0 - 2.0*c.Q1 + 2.0*c.Q1*math.Cos(2.0*math.Pi*quefrency*f0)
+		}
+		ceps[i] *= complex(smoothing*recovery, 0.0)
+	}
+
+	smoothed := fft.FFT(ceps)
+	envelope := make([]float64, n)
+	for k := range envelope {
+		envelope[k] = math.Exp(real(smoothed[k]))
+	}
+	return envelope
+}
+
+// interpolateSpectrum returns a spectrum at a fractional bin by linear
+// interpolation.
+func interpolateSpectrum(power []float64, bin float64) float64 {
+	if bin <= 0.0 {
+		return power[0]
+	}
+	if bin >= float64(len(power)-1) {
+		return power[len(power)-1]
+	}
+	i := int(bin)
+	return power[i] + (bin-float64(i))*(power[i+1]-power[i])
+}
+
+// SpectrumToMCep converts a power spectral envelope (FFTSize/2+1 bins) to
+// mel-cepstrum through mgcep.LogAmp2MCep. The log power spectrum is given
+// as input: LogAmp2MCep halves only c(0), and for m >= 1 the real cepstrum
+// of the log power spectrum already equals the minimum phase cepstrum of
+// the amplitude envelope, so the result represents exp(sum c(m)z^-m) whose
+// power spectrum is the envelope.
+func SpectrumToMCep(spectrum []float64, order int, alpha float64) []float64 {
+	fftSize := (len(spectrum) - 1) * 2
+	logPower := make([]float64, fftSize)
+	for k, val := range spectrum {
+		logPower[k] = math.Log(val + safeGuardMinimum)
+	}
+	for k := len(spectrum); k < fftSize; k++ {
+		logPower[k] = logPower[fftSize-k]
+	}
+	return mgcep.LogAmp2MCep(logPower, order, alpha)
+}
@@ -0,0 +1,132 @@
+package world
+
+import (
+	"github.com/mjibson/go-dsp/fft"
+	"github.com/r9y9/gossp/mgcep"
+	"math"
+	"testing"
+)
+
+// createPulseTrain returns a pulse train filtered by a second-order
+// resonator, and the power response of the resonator.
+func createFilteredPulseTrain(freq float64, sampleRate, length int,
+	resonance, bandwidth float64) ([]float64, func(float64) float64) {
+	fs := float64(sampleRate)
+	r := math.Exp(-math.Pi * bandwidth / fs)
+	theta := 2.0 * math.Pi * resonance / fs
+	a1, a2 := -2.0*r*math.Cos(theta), r*r
+
+	x := make([]float64, length)
+	period := fs / freq
+	for p := 0.0; p < float64(length); p += period {
+		x[int(p)] = 1.0
+	}
+	for i := range x {
+		if i >= 1 {
+			x[i] -= a1 * x[i-1]
+		}
+		if i >= 2 {
+			x[i] -= a2 * x[i-2]
+		}
+	}
+
+	response := func(frequency float64) float64 {
+		w := 2.0 * math.Pi * frequency / fs
+		re := 1.0 + a1*math.Cos(w) + a2*math.Cos(2.0*w)
+		im := -a1*math.Sin(w) - a2*math.Sin(2.0*w)
+		return 1.0 / (re*re + im*im)
+	}
+	return x, response
+}
+
+func TestCheapTrick(t *testing.T) {
+	sampleRate := 16000
+	c := NewCheapTrick(sampleRate)
+
+	if c.FFTSize != 1024 {
+		t.Errorf("FFT size is %d, want 1024.", c.FFTSize)
+	}
+
+	for _, freq := range []float64{100.0, 160.0, 250.0} {
+		x, response := createFilteredPulseTrain(freq, sampleRate, sampleRate/2,
+			1000.0, 300.0)
+		envelope := c.EstimateOneFrame(x, float64(sampleRate/4), freq)
+		if len(envelope) != c.FFTSize/2+1 {
+			t.Fatalf("The length of envelope is %d, want %d.",
+				len(envelope), c.FFTSize/2+1)
+		}
+
+		// The envelope must follow the power spectral density without
+		// harmonic structure.
+		df := float64(sampleRate) / float64(c.FFTSize)
+		var differences []float64
+		mean := 0.0
+		for k := int(300.0 / df); k < int(6000.0/df); k++ {
+			// Power spectral density of the filtered pulse train
+			density := response(float64(k)*df) * freq / float64(sampleRate)
+			d := 10.0 * math.Log10(envelope[k]/density)
+			differences = append(differences, d)
+			mean += d
+		}
+		mean /= float64(len(differences))
+		if math.Abs(mean) > 1.0 {
+			t.Errorf("%f Hz: the level of the envelope differs by %f dB.", freq, mean)
+		}
+		for _, d := range differences {
+			if math.Abs(d-mean) > 1.5 {
+				t.Errorf("%f Hz: the envelope deviates %f dB from the filter response.",
+					freq, d-mean)
+				break
+			}
+		}
+	}
+}
+
+func TestSpectrumToMCep(t *testing.T) {
+	sampleRate := 16000
+	c := NewCheapTrick(sampleRate)
+	x, _ := createFilteredPulseTrain(120.0, sampleRate, sampleRate/2, 1000.0, 300.0)
+	envelope := c.EstimateOneFrame(x, float64(sampleRate/4), 120.0)
+
+	// Power spectrum of the minimum phase response without frequency
+	// warping reconstructs the envelope
+	mc := SpectrumToMCep(envelope, 60, 0.0)
+	response := fft.FFTReal(mgcep.C2IR(mc, c.FFTSize))
+	for k := range envelope {
+		logPower := math.Log(real(response[k])*real(response[k]) +
+			imag(response[k])*imag(response[k]))
+		expected := math.Log(envelope[k])
+		if math.Abs(logPower-expected) > 0.1 {
+			t.Errorf("Bin %d: log power %f, want %f.", k, logPower, expected)
+		}
+	}
+
+	// Mel-cepstrum of the power spectrum of a minimum phase response
+	expected := []float64{0.5, 0.4, -0.2, 0.1}
+	impulseResponse := mgcep.C2IR(mgcep.FreqT(expected, c.FFTSize-1, -0.41),
+		c.FFTSize)
+	spectrum := fft.FFTReal(impulseResponse)
+	power := make([]float64, c.FFTSize/2+1)
+	for k := range power {
+		power[k] = real(spectrum[k])*real(spectrum[k]) +
+			imag(spectrum[k])*imag(spectrum[k])
+	}
+	mc = SpectrumToMCep(power, len(expected)-1, 0.41)
+	for i := range expected {
+		if math.Abs(mc[i]-expected[i]) > 1.0e-6 {
+			t.Errorf("Coefficient %d is %f, want %f.", i, mc[i], expected[i])
+		}
+	}
+
+	// Flat spectrum gives zero except for the first coefficient
+	flat := make([]float64, len(envelope))
+	for k := range flat {
+		flat[k] = 1.0
+	}
+	mc = SpectrumToMCep(flat, 24, 0.41)
+	for i := 1; i < len(mc); i++ {
+		if math.Abs(mc[i]) > 1.0e-6 {
+			t.Errorf("Coefficient %d is %f, want zero.", i, mc[i])
+		}
+	}
+}
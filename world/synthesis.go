@@ -0,0 +1,214 @@
+package world
+
+import (
+	"github.com/mjibson/go-dsp/fft"
+	"math"
+	"math/cmplx"
+	"math/rand"
+)
+
+// DefaultSeed is the seed of the random number generator used unless Seed
+// is called.
+const DefaultSeed = 1
+
+// Synthesizer represents a speech synthesizer that overlap-adds minimum
+// phase responses pitch-synchronously. Each response consists of the
+// periodic component given by the spectral envelope and the aperiodic
+// component given by noise shaped by the envelope and aperiodicity.
+// Unvoiced segments are synthesized only by the aperiodic component with
+// DefaultUnvoicedF0 as the interval of responses.
+type Synthesizer struct {
+	rand *rand.Rand
+}
+
+// NewSynthesizer returns a new Synthesizer instance.
+func NewSynthesizer() *Synthesizer {
+	return &Synthesizer{
+		rand: rand.New(rand.NewSource(DefaultSeed)),
+	}
+}
+
+// Seed initializes the random number generator with a given seed.
+func (s *Synthesizer) Seed(seed int64) {
+	s.rand = rand.New(rand.NewSource(seed))
+}
+
+// Synthesis synthesizes a speech signal of FrameShift*len(F0) samples from
+// speech parameters. It returns ErrNumFramesMismatch if the spectral
+// envelope or aperiodicity does not have the same number of frames as f0.
+func (s *Synthesizer) Synthesis(p *Parameters) ([]float64, error) {
+	if len(p.Spectrum) != len(p.F0) || len(p.BandAp) != len(p.F0) {
+		return nil, ErrNumFramesMismatch
+	}
+
+	fs := float64(p.SampleRate)
+	fftSize := p.FFTSize()
+	length := p.FrameShift * len(p.F0)
+	synthesized := make([]float64, length)
+
+	// f0 of each sample, which is linearly interpolated between frames
+	f0AtSample := func(n int) (float64, bool) {
+		i := n / p.FrameShift
+		next := i + 1
+		if next >= len(p.F0) {
+			next = len(p.F0) - 1
+		}
+		if p.F0[i] == 0.0 || p.F0[next] == 0.0 {
+			return DefaultUnvoicedF0, false
+		}
+		w := float64(n-i*p.FrameShift) / float64(p.FrameShift)
+		return (1.0-w)*p.F0[i] + w*p.F0[next], true
+	}
+
+	phase := 0.0
+	previousVoiced := false
+	for n := 0; n < length; n++ {
+		f0, voiced := f0AtSample(n)
+		if n == 0 || voiced != previousVoiced {
+			// a response is placed at the onset of each segment
+			phase = 1.0
+		}
+		previousVoiced = voiced
+		if phase >= 1.0 {
+			phase -= math.Floor(phase)
+			// fractional position of the response
+			position := float64(n) - phase*fs/f0
+			s.addResponse(synthesized, p, position, f0, voiced, fftSize)
+		}
+		phase += f0 / fs
+	}
+
+	return synthesized, nil
+}
+
+// addResponse overlap-adds a response at a given position in samples.
+func (s *Synthesizer) addResponse(synthesized []float64, p *Parameters,
+	position, f0 float64, voiced bool, fftSize int) {
+	fs := float64(p.SampleRate)
+	period := fs / f0
+	spectrum := interpolateFrames(p.Spectrum, position/float64(p.FrameShift))
+	bandAp := interpolateFrames(p.BandAp, position/float64(p.FrameShift))
+
+	// Power spectra of periodic and aperiodic components. A pulse train
+	// whose responses have a power spectrum of P*period has the power
+	// spectral density of P.
+	periodicPower := make([]float64, fftSize/2+1)
+	aperiodicPower := make([]float64, fftSize/2+1)
+	for k := range periodicPower {
+		ap := 1.0
+		if voiced {
+			ap = bandValue(bandAp, p.BandEdges, float64(k)*fs/float64(fftSize))
+			ap = math.Max(0.0, math.Min(ap, 1.0))
+		}
+		periodicPower[k] = spectrum[k] * (1.0 - ap) * period
+		aperiodicPower[k] = spectrum[k] * ap
+	}
+
+	start := int(math.Floor(position))
+	fraction := position - float64(start)
+
+	if voiced {
+		periodic := minimumPhaseSpectrum(periodicPower)
+		response := make([]complex128, fftSize)
+		for k := range response {
+			// fractional delay by the linear phase of signed frequency
+			frequency := k
+			if k > fftSize/2 {
+				frequency = k - fftSize
+			}
+			delay := cmplx.Rect(1.0,
+				-2.0*math.Pi*float64(frequency)*fraction/float64(fftSize))
+			response[k] = periodic[k] * delay
+		}
+
+		// The latter half of the response corresponds to negative time,
+		// which arises from the fractional delay.
+		for i, val := range fft.IFFT(response) {
+			index := start + i
+			if i >= fftSize/2 {
+				index -= fftSize
+			}
+			if index >= 0 && index < len(synthesized) {
+				synthesized[index] += real(val)
+			}
+		}
+	}
+
+	// White noise of one period shaped by the aperiodic component. Both
+	// are zero-padded to twice the FFT size so that the tail of the
+	// convolution does not wrap around.
+	noise := make([]float64, 2*fftSize)
+	for i := 0; i < int(period+0.5) && i < fftSize; i++ {
+		noise[i] = s.rand.NormFloat64()
+	}
+	impulseResponse := make([]float64, 2*fftSize)
+	for i, val := range fft.IFFT(minimumPhaseSpectrum(aperiodicPower)) {
+		impulseResponse[i] = real(val)
+	}
+	noiseSpectrum := fft.FFTReal(noise)
+	aperiodic := fft.FFTReal(impulseResponse)
+	for k := range aperiodic {
+		aperiodic[k] *= noiseSpectrum[k]
+	}
+	for i, val := range fft.IFFT(aperiodic) {
+		if index := start + i; index >= 0 && index < len(synthesized) {
+			synthesized[index] += real(val)
+		}
+	}
+}
+
+// minimumPhaseSpectrum returns the whole spectrum (fftSize bins) of the
+// minimum phase response whose power spectrum is power (fftSize/2+1 bins).
+func minimumPhaseSpectrum(power []float64) []complex128 {
+	fftSize := (len(power) - 1) * 2
+	logAmp := make([]float64, fftSize)
+	for k, val := range power {
+		logAmp[k] = 0.5 * math.Log(val+safeGuardMinimum)
+	}
+	for k := len(power); k < fftSize; k++ {
+		logAmp[k] = logAmp[fftSize-k]
+	}
+	ceps := fft.IFFTReal(logAmp)
+
+	// Folding the cepstrum makes the response causal
+	for i := 1; i < fftSize/2; i++ {
+		ceps[i] *= 2.0
+	}
+	for i := fftSize/2 + 1; i < fftSize; i++ {
+		ceps[i] = 0.0
+	}
+
+	spectrum := fft.FFT(ceps)
+	for k := range spectrum {
+		spectrum[k] = cmplx.Exp(spectrum[k])
+	}
+	return spectrum
+}
+
+// interpolateFrames returns a frame at a fractional frame index by linear
+// interpolation.
+func interpolateFrames(frames [][]float64, index float64) []float64 {
+	i := int(math.Floor(index))
+	if i < 0 {
+		return frames[0]
+	}
+	if i >= len(frames)-1 {
+		return frames[len(frames)-1]
+	}
+	w := index - float64(i)
+	interpolated := make([]float64, len(frames[i]))
+	for k := range interpolated {
+		interpolated[k] = (1.0-w)*frames[i][k] + w*frames[i+1][k]
+	}
+	return interpolated
+}
+
+// bandValue returns the value of the band that contains a given frequency.
+func bandValue(values, bandEdges []float64, frequency float64) float64 {
+	for j, edge := range bandEdges {
+		if frequency < edge {
+			return values[j]
+		}
+	}
+	return values[len(bandEdges)]
+}
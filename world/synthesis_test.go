@@ -0,0 +1,163 @@
+package world
+
+import (
+	"github.com/r9y9/gossp/f0"
+	"math"
+	"testing"
+)
+
+func power(x []float64) float64 {
+	sum := 0.0
+	for _, val := range x {
+		sum += val * val
+	}
+	return sum / float64(len(x))
+}
+
+func TestAnalysisSynthesis(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	x, _ := createFilteredPulseTrain(150.0, sampleRate, sampleRate/2,
+		800.0, 1000.0)
+
+	p, err := NewAnalyzer(sampleRate, frameShift).Analyze(x)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(p.Spectrum) != len(p.F0) || len(p.BandAp) != len(p.F0) {
+		t.Fatalf("The number of frames are inconsistent: %d, %d and %d.",
+			len(p.F0), len(p.Spectrum), len(p.BandAp))
+	}
+
+	y, err := NewSynthesizer().Synthesis(p)
+	if err != nil {
+		t.Fatalf("Synthesis returned error: %v", err)
+	}
+	if len(y) != frameShift*len(p.F0) {
+		t.Errorf("The length of synthesized speech is %d, want %d.",
+			len(y), frameShift*len(p.F0))
+	}
+
+	// Power and f0 must be preserved except for edges
+	margin := sampleRate / 10
+	originalPower := power(x[margin : len(x)-margin])
+	synthesizedPower := power(y[margin : len(x)-margin])
+	if d := 10.0 * math.Log10(synthesizedPower/originalPower); math.Abs(d) > 1.0 {
+		t.Errorf("Power of synthesized speech differs by %f dB.", d)
+	}
+
+	f0Sequence := f0.DIO(y, sampleRate, frameShift, DefaultF0Floor, DefaultF0Ceil)
+	f0Sequence = f0.StoneMask(y, sampleRate, frameShift, f0Sequence)
+	for i := 20; i < len(f0Sequence)-20; i++ {
+		if math.Abs(f0Sequence[i]-150.0) > 1.0 {
+			t.Errorf("f0 of synthesized speech is %f at frame %d, want 150 Hz.",
+				f0Sequence[i], i)
+		}
+	}
+}
+
+func TestAnalyzerMismatch(t *testing.T) {
+	sampleRate, frameShift := 16000, 80
+	x, _ := createFilteredPulseTrain(150.0, sampleRate, sampleRate/10,
+		800.0, 1000.0)
+
+	a := NewAnalyzer(sampleRate, frameShift)
+	a.F0Estimator = f0.NewDIOEstimator(sampleRate, frameShift*2,
+		DefaultF0Floor, DefaultF0Ceil)
+	if _, err := a.Analyze(x); err != ErrFrameShiftMismatch {
+		t.Errorf("Analyze returned %v, want %v.", err, ErrFrameShiftMismatch)
+	}
+	a.F0Estimator = f0.NewDIOEstimator(sampleRate/2, frameShift,
+		DefaultF0Floor, DefaultF0Ceil)
+	if _, err := a.Analyze(x); err != ErrSampleRateMismatch {
+		t.Errorf("Analyze returned %v, want %v.", err, ErrSampleRateMismatch)
+	}
+}
+
+func TestSynthesisUnvoiced(t *testing.T) {
+	sampleRate, frameShift, fftSize := 16000, 80, 1024
+	numFrames := 100
+	p := &Parameters{
+		SampleRate: sampleRate,
+		FrameShift: frameShift,
+		F0:         make([]float64, numFrames),
+		Spectrum:   make([][]float64, numFrames),
+		BandEdges:  []float64{4000.0},
+		BandAp:     make([][]float64, numFrames),
+	}
+	for i := range p.Spectrum {
+		p.Spectrum[i] = make([]float64, fftSize/2+1)
+		for k := range p.Spectrum[i] {
+			p.Spectrum[i][k] = 0.01
+		}
+		p.BandAp[i] = []float64{0.0, 0.0}
+	}
+
+	// Unvoiced frames are white noise with the power of the spectrum
+	s := NewSynthesizer()
+	s.Seed(0)
+	y, err := s.Synthesis(p)
+	if err != nil {
+		t.Fatalf("Synthesis returned error: %v", err)
+	}
+	if d := 10.0 * math.Log10(power(y)/0.01); math.Abs(d) > 1.0 {
+		t.Errorf("Power of unvoiced speech differs by %f dB.", d)
+	}
+}
+
+func TestSynthesisEmpty(t *testing.T) {
+	p := &Parameters{SampleRate: 16000, FrameShift: 80}
+	if fftSize := p.FFTSize(); fftSize != 0 {
+		t.Errorf("FFT size of empty parameters is %d, want 0.", fftSize)
+	}
+	y, err := NewSynthesizer().Synthesis(p)
+	if err != nil || len(y) != 0 {
+		t.Errorf("Synthesis of empty parameters returned %v and %v, want empty.",
+			y, err)
+	}
+
+	p.F0 = make([]float64, 10)
+	if _, err := NewSynthesizer().Synthesis(p); err != ErrNumFramesMismatch {
+		t.Errorf("Synthesis returned %v, want %v.", err, ErrNumFramesMismatch)
+	}
+}
+
+func TestAperiodicResponseIsCausal(t *testing.T) {
+	sampleRate, frameShift, fftSize := 16000, 80, 256
+	numFrames := 20
+	p := &Parameters{
+		SampleRate: sampleRate,
+		FrameShift: frameShift,
+		F0:         make([]float64, numFrames),
+		Spectrum:   make([][]float64, numFrames),
+		BandEdges:  []float64{4000.0},
+		BandAp:     make([][]float64, numFrames),
+	}
+	// A sharp resonance gives a response longer than half the FFT size
+	for i := range p.Spectrum {
+		p.Spectrum[i] = make([]float64, fftSize/2+1)
+		for k := range p.Spectrum[i] {
+			d := float64(k - fftSize/8)
+			p.Spectrum[i][k] = 1.0 / (d*d + 0.01)
+		}
+		p.BandAp[i] = []float64{1.0, 1.0}
+	}
+
+	s := NewSynthesizer()
+	synthesized := make([]float64, frameShift*numFrames)
+	position := 800.0
+	s.addResponse(synthesized, p, position, DefaultUnvoicedF0, false, fftSize)
+	for i := 0; i < int(position); i++ {
+		if synthesized[i] != 0.0 {
+			t.Fatalf("Response at %d before the position %f is %f, want 0.",
+				i, position, synthesized[i])
+		}
+	}
+	// The tail continues beyond the FFT size
+	tail := 0.0
+	for i := int(position) + fftSize; i < int(position)+2*fftSize; i++ {
+		tail += synthesized[i] * synthesized[i]
+	}
+	if tail == 0.0 {
+		t.Errorf("Tail of the response is missing.")
+	}
+}
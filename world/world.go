@@ -0,0 +1,25 @@
+// Package world provides support for WORLD-style speech analysis and
+// synthesis, which decomposes speech into f0, spectral envelope and
+// aperiodicity. See https://github.com/mmorise/World for the original
+// implementation.
+package world
+
+const (
+	safeGuardMinimum = 1.0e-12
+)
+
+func nextPow2(n int) int {
+	size := 1
+	for size < n {
+		size *= 2
+	}
+	return size
+}
+
+// sampleAt returns x[i] or zero if i is out of range.
+func sampleAt(x []float64, i int) float64 {
+	if i < 0 || i >= len(x) {
+		return 0.0
+	}
+	return x[i]
+}